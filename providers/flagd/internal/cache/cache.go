@@ -1,16 +1,27 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
 	"github.com/go-logr/logr"
 	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/open-feature/flagd/core/pkg/model"
 )
 
 type Type string
 
 const (
-	LRUValue      Type = "lru"
-	InMemValue    Type = "mem"
-	DisabledValue Type = "disabled"
+	LRUValue        Type = "lru"
+	InMemValue      Type = "mem"
+	ContextualValue Type = "contextual"
+	TTLValue        Type = "ttl"
+	DisabledValue   Type = "disabled"
 )
 
 // Cache is the contract of the cache implementation
@@ -23,13 +34,20 @@ type Cache[K comparable, V any] interface {
 
 type Service struct {
 	cacheEnabled bool
+	contextual   bool
 	cache        Cache[string, interface{}]
+	counting     *counting[string, interface{}]
+
+	// generations of contextual entries, bumped on flag invalidation
+	generations map[string]uint64
+	genMux      sync.RWMutex
 }
 
-func NewCacheService(cacheType Type, maxCacheSize int, log logr.Logger) *Service {
+func NewCacheService(cacheType Type, maxCacheSize int, ttl time.Duration, log logr.Logger) *Service {
 	var c Cache[string, interface{}]
 	var err error
 	var cacheEnabled bool
+	var contextual bool
 
 	// setup cache
 	switch cacheType {
@@ -43,16 +61,40 @@ func NewCacheService(cacheType Type, maxCacheSize int, log logr.Logger) *Service
 	case InMemValue:
 		c = NewInMemory[string, interface{}]()
 		cacheEnabled = true
+	case ContextualValue:
+		// contextual entries multiply with the number of distinct contexts, hence always bounded
+		c, err = lru.New[string, interface{}](maxCacheSize)
+		if err != nil {
+			log.Error(err, "init contextual lru cache")
+		} else {
+			cacheEnabled = true
+			contextual = true
+		}
+	case TTLValue:
+		if ttl <= 0 {
+			log.Error(errors.New("ttl must be positive"), "init ttl cache")
+		} else {
+			c = NewExpiring[string, interface{}](maxCacheSize, ttl)
+			cacheEnabled = true
+		}
 	case DisabledValue:
 	default:
 		cacheEnabled = false
 		c = nil
 	}
 
-	return &Service{
+	service := &Service{
 		cacheEnabled: cacheEnabled,
-		cache:        c,
+		contextual:   contextual,
+		generations:  map[string]uint64{},
 	}
+
+	if cacheEnabled {
+		service.counting = newCounting(c)
+		service.cache = service.counting
+	}
+
+	return service
 }
 
 func (s *Service) GetCache() Cache[string, interface{}] {
@@ -63,9 +105,84 @@ func (s *Service) IsEnabled() bool {
 	return s.cacheEnabled
 }
 
+// Stats returns a snapshot of the cache statistics. All values are zero if caching was never enabled.
+func (s *Service) Stats() Stats {
+	if s.counting == nil {
+		return Stats{}
+	}
+
+	return s.counting.stats()
+}
+
+// IsContextual returns true if cache entries are keyed on the evaluation context in addition to the flag key
+func (s *Service) IsContextual() bool {
+	return s.contextual
+}
+
 func (s *Service) Disable() {
 	if s.IsEnabled() {
 		s.cacheEnabled = false
 		s.cache.Purge()
 	}
 }
+
+// Key derives the cache key of a flag evaluation. The returned boolean is false if the evaluation can not be cached,
+// either because caching is disabled or because the evaluation context can not be hashed.
+func (s *Service) Key(flagKey string, evalCtx map[string]interface{}) (string, bool) {
+	if !s.IsEnabled() {
+		return "", false
+	}
+
+	if !s.contextual {
+		return flagKey, true
+	}
+
+	hash, err := hashContext(evalCtx)
+	if err != nil {
+		return "", false
+	}
+
+	s.genMux.RLock()
+	generation := s.generations[flagKey]
+	s.genMux.RUnlock()
+
+	return flagKey + "\x00" + strconv.FormatUint(generation, 10) + "\x00" + hash, true
+}
+
+// ShouldCache returns true if an evaluation with the given reason may be stored in the cache
+func (s *Service) ShouldCache(reason string) bool {
+	if reason == model.StaticReason {
+		return true
+	}
+
+	// targeting results only depend on the evaluation context they were resolved with
+	return s.contextual && reason == model.TargetingMatchReason
+}
+
+// Invalidate removes all cached evaluations of the given flag
+func (s *Service) Invalidate(flagKey string) {
+	if !s.IsEnabled() {
+		return
+	}
+
+	if !s.contextual {
+		s.cache.Remove(flagKey)
+		return
+	}
+
+	// entries of older generations are no longer reachable and age out of the lru
+	s.genMux.Lock()
+	s.generations[flagKey]++
+	s.genMux.Unlock()
+}
+
+// hashContext derives a stable hash of the evaluation context. Map keys are sorted by the json encoder.
+func hashContext(evalCtx map[string]interface{}) (string, error) {
+	b, err := json.Marshal(evalCtx)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
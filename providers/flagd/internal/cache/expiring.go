@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type expiringEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// Expiring is a cache with a fixed time to live for every entry and an optional maximum size.
+// Expired entries are dropped lazily on access and on insertion, hence no background routine is required.
+type Expiring[K comparable, V any] struct {
+	ttl     time.Duration
+	maxSize int
+
+	// entries are kept in insertion order, which equals the order of expiry given the fixed ttl
+	order  *list.List
+	values map[K]*list.Element
+	mux    *sync.Mutex
+	now    func() time.Time
+
+	// expired counts the entries dropped because their time to live elapsed
+	expired uint64
+}
+
+// NewExpiring creates an Expiring cache. A maxSize of zero or less disables the size limit.
+func NewExpiring[K comparable, V any](maxSize int, ttl time.Duration) *Expiring[K, V] {
+	return &Expiring[K, V]{
+		ttl:     ttl,
+		maxSize: maxSize,
+		order:   list.New(),
+		values:  make(map[K]*list.Element),
+		mux:     &sync.Mutex{},
+		now:     time.Now,
+	}
+}
+
+func (e *Expiring[K, V]) Add(key K, value V) (evicted bool) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	now := e.now()
+	e.removeExpired(now)
+
+	if element, ok := e.values[key]; ok {
+		entry := element.Value.(*expiringEntry[K, V])
+		entry.value = value
+		entry.expiresAt = now.Add(e.ttl)
+		e.order.MoveToBack(element)
+		return false
+	}
+
+	e.values[key] = e.order.PushBack(&expiringEntry[K, V]{
+		key:       key,
+		value:     value,
+		expiresAt: now.Add(e.ttl),
+	})
+
+	if e.maxSize > 0 && e.order.Len() > e.maxSize {
+		e.removeElement(e.order.Front())
+		return true
+	}
+
+	return false
+}
+
+func (e *Expiring[K, V]) Get(key K) (value V, ok bool) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	element, ok := e.values[key]
+	if !ok {
+		return value, false
+	}
+
+	entry := element.Value.(*expiringEntry[K, V])
+	if !e.now().Before(entry.expiresAt) {
+		e.removeElement(element)
+		e.expired++
+		return value, false
+	}
+
+	return entry.value, true
+}
+
+func (e *Expiring[K, V]) Remove(key K) (present bool) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	element, ok := e.values[key]
+	if ok {
+		e.removeElement(element)
+	}
+
+	return ok
+}
+
+func (e *Expiring[K, V]) Purge() {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	e.order.Init()
+	e.values = make(map[K]*list.Element)
+}
+
+// removeExpired drops expired entries from the front of the insertion order. Must be called with the lock held.
+func (e *Expiring[K, V]) removeExpired(now time.Time) {
+	for element := e.order.Front(); element != nil; element = e.order.Front() {
+		if now.Before(element.Value.(*expiringEntry[K, V]).expiresAt) {
+			return
+		}
+		e.removeElement(element)
+		e.expired++
+	}
+}
+
+// Expirations returns the number of entries dropped because their time to live elapsed. Expired entries are dropped
+// lazily, hence counted once they are accessed or swept on insertion.
+func (e *Expiring[K, V]) Expirations() uint64 {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	return e.expired
+}
+
+// removeElement drops a single entry. Must be called with the lock held.
+func (e *Expiring[K, V]) removeElement(element *list.Element) {
+	e.order.Remove(element)
+	delete(e.values, element.Value.(*expiringEntry[K, V]).key)
+}
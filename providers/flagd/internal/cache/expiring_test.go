@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiring(t *testing.T) {
+	now := time.Now()
+	c := NewExpiring[string, int](2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("expected a=1 to be cached, got %v (found: %v)", v, ok)
+	}
+
+	// size limit evicts the oldest entry
+	if evicted := c.Add("c", 3); !evicted {
+		t.Errorf("expected an eviction once the size limit was exceeded")
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("expected a to be evicted")
+	}
+
+	// entries expire after the ttl
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("expected b to be expired")
+	}
+
+	// re-adding refreshes the ttl
+	c.Add("c", 4)
+	now = now.Add(30 * time.Second)
+	if v, ok := c.Get("c"); !ok || v != 4 {
+		t.Errorf("expected c=4 to be cached, got %v (found: %v)", v, ok)
+	}
+}
+
+func TestExpiringUnbounded(t *testing.T) {
+	now := time.Now()
+	c := NewExpiring[int, int](0, time.Second)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		if c.Add(i, i) {
+			t.Fatalf("expected no eviction without a size limit")
+		}
+	}
+
+	// insertion sweeps expired entries
+	now = now.Add(time.Second)
+	c.Add(100, 100)
+	if len(c.values) != 1 {
+		t.Errorf("expected expired entries to be removed on insertion, %d entries remain", len(c.values))
+	}
+}
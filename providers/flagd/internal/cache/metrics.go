@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+const (
+	// ScopeName is the instrumentation scope name of the cache metrics
+	ScopeName = "github.com/open-feature/go-sdk-contrib/providers/flagd"
+
+	cacheHits      = "feature_flag.flagd.cache_hit_total"
+	cacheMisses    = "feature_flag.flagd.cache_miss_total"
+	cacheEvictions = "feature_flag.flagd.cache_eviction_total"
+	cachePurges    = "feature_flag.flagd.cache_purge_total"
+)
+
+// RegisterMetrics registers observable counters reporting the statistics of the cache service with the meter provider.
+func RegisterMetrics(provider metric.MeterProvider, s *Service) (metric.Registration, error) {
+	meter := provider.Meter(ScopeName)
+
+	hits, err := meter.Int64ObservableCounter(cacheHits, metric.WithDescription("flagd cache hit counter"))
+	if err != nil {
+		return nil, err
+	}
+
+	misses, err := meter.Int64ObservableCounter(cacheMisses, metric.WithDescription("flagd cache miss counter"))
+	if err != nil {
+		return nil, err
+	}
+
+	evictions, err := meter.Int64ObservableCounter(cacheEvictions, metric.WithDescription("flagd cache eviction counter"))
+	if err != nil {
+		return nil, err
+	}
+
+	purges, err := meter.Int64ObservableCounter(cachePurges, metric.WithDescription("flagd cache purge counter"))
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := metric.WithAttributes(semconv.FeatureFlagProviderName("flagd"))
+
+	return meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		stats := s.Stats()
+		observer.ObserveInt64(hits, int64(stats.Hits), attributes)
+		observer.ObserveInt64(misses, int64(stats.Misses), attributes)
+		observer.ObserveInt64(evictions, int64(stats.Evictions), attributes)
+		observer.ObserveInt64(purges, int64(stats.Purges), attributes)
+		return nil
+	}, hits, misses, evictions, purges)
+}
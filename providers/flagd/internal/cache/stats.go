@@ -0,0 +1,74 @@
+package cache
+
+import "sync/atomic"
+
+// Stats is a snapshot of the cache statistics
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Purges    uint64
+}
+
+// expiring is implemented by caches dropping entries once their time to live elapsed
+type expiring interface {
+	Expirations() uint64
+}
+
+// counting decorates a Cache and records hits, misses, evictions and purges
+type counting[K comparable, V any] struct {
+	cache Cache[K, V]
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	purges    atomic.Uint64
+}
+
+func newCounting[K comparable, V any](cache Cache[K, V]) *counting[K, V] {
+	return &counting[K, V]{cache: cache}
+}
+
+func (c *counting[K, V]) Add(key K, value V) (evicted bool) {
+	evicted = c.cache.Add(key, value)
+	if evicted {
+		c.evictions.Add(1)
+	}
+
+	return evicted
+}
+
+func (c *counting[K, V]) Get(key K) (value V, ok bool) {
+	value, ok = c.cache.Get(key)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+
+	return value, ok
+}
+
+func (c *counting[K, V]) Remove(key K) (present bool) {
+	return c.cache.Remove(key)
+}
+
+func (c *counting[K, V]) Purge() {
+	c.cache.Purge()
+	c.purges.Add(1)
+}
+
+// stats returns the statistics, expirations count as evictions
+func (c *counting[K, V]) stats() Stats {
+	evictions := c.evictions.Load()
+	if e, ok := c.cache.(expiring); ok {
+		evictions += e.Expirations()
+	}
+
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: evictions,
+		Purges:    c.purges.Load(),
+	}
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestServiceStats(t *testing.T) {
+	s := NewCacheService(LRUValue, 1, 0, logr.Discard())
+
+	s.GetCache().Add("a", 1)
+	s.GetCache().Get("a")
+	s.GetCache().Get("b")
+	s.GetCache().Add("b", 2)
+	s.Disable()
+
+	want := Stats{Hits: 1, Misses: 1, Evictions: 1, Purges: 1}
+	if got := s.Stats(); got != want {
+		t.Errorf("unexpected stats, expected %+v, got %+v", want, got)
+	}
+}
+
+func TestServiceStatsExpirations(t *testing.T) {
+	s := NewCacheService(TTLValue, 1, time.Minute, logr.Discard())
+	now := time.Now()
+	s.counting.cache.(*Expiring[string, interface{}]).now = func() time.Time { return now }
+
+	s.GetCache().Add("a", 1)
+	s.GetCache().Add("b", 2)
+	now = now.Add(time.Minute)
+	s.GetCache().Get("b")
+
+	want := Stats{Misses: 1, Evictions: 2}
+	if got := s.Stats(); got != want {
+		t.Errorf("unexpected stats, expected %+v, got %+v", want, got)
+	}
+}
+
+func TestServiceStatsDisabled(t *testing.T) {
+	s := NewCacheService(DisabledValue, 0, 0, logr.Discard())
+
+	if got := s.Stats(); got != (Stats{}) {
+		t.Errorf("expected empty stats for disabled cache, got %+v", got)
+	}
+}
@@ -0,0 +1,87 @@
+package certificate
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileStamp identifies the version of a file on disk
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// ClientCertificate provides the client certificate for mutual TLS.
+// The key pair is reloaded when its files change on disk, so rotated certificates are picked up by new connections.
+// If the rotated key pair can not be loaded yet, e.g. as only the certificate was replaced so far, the previous key
+// pair keeps being used and loading is retried on the next handshake.
+type ClientCertificate struct {
+	certPath string
+	keyPath  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	certStamp fileStamp
+	keyStamp  fileStamp
+}
+
+// NewClientCertificate loads the PEM encoded key pair at certPath and keyPath
+func NewClientCertificate(certPath string, keyPath string) (*ClientCertificate, error) {
+	c := &ClientCertificate{
+		certPath: certPath,
+		keyPath:  keyPath,
+	}
+
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetClientCertificate returns the current key pair, it is meant to be used as tls.Config.GetClientCertificate
+func (c *ClientCertificate) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.reload(); err != nil && c.cert == nil {
+		return nil, err
+	}
+	return c.cert, nil
+}
+
+// reload loads the key pair if its files changed since they were last loaded. The caller must hold the lock.
+func (c *ClientCertificate) reload() error {
+	certStamp, err := stat(c.certPath)
+	if err != nil {
+		return err
+	}
+	keyStamp, err := stat(c.keyPath)
+	if err != nil {
+		return err
+	}
+
+	if c.cert != nil && certStamp == c.certStamp && keyStamp == c.keyStamp {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	c.cert = &cert
+	c.certStamp = certStamp
+	c.keyStamp = keyStamp
+	return nil
+}
+
+func stat(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, fmt.Errorf("failed to read client certificate file: %w", err)
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
@@ -0,0 +1,137 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeKeyPair writes a self-signed key pair with the given serial number to certPath and keyPath
+func writeKeyPair(t *testing.T, certPath string, keyPath string, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func serialOf(t *testing.T, c *ClientCertificate) int64 {
+	t.Helper()
+
+	cert, err := c.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.SerialNumber.Int64()
+}
+
+// touch moves the modification time of the file, as rotations within the timestamp granularity are not detected
+func touch(t *testing.T, path string, offset time.Duration) {
+	t.Helper()
+
+	modTime := time.Now().Add(offset)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientCertificateReloadsOnRotation(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	writeKeyPair(t, certPath, keyPath, 1)
+
+	c, err := NewClientCertificate(certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if serial := serialOf(t, c); serial != 1 {
+		t.Fatalf("expected initial certificate, got serial %d", serial)
+	}
+
+	// rotate the key pair
+	writeKeyPair(t, certPath, keyPath, 2)
+	touch(t, certPath, time.Minute)
+	touch(t, keyPath, time.Minute)
+
+	if serial := serialOf(t, c); serial != 2 {
+		t.Fatalf("expected rotated certificate, got serial %d", serial)
+	}
+}
+
+func TestClientCertificateKeepsPreviousOnInvalidRotation(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	writeKeyPair(t, certPath, keyPath, 1)
+
+	c, err := NewClientCertificate(certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the certificate is rotated so far, it does not match the key
+	otherDir := t.TempDir()
+	writeKeyPair(t, filepath.Join(otherDir, "client.crt"), filepath.Join(otherDir, "client.key"), 2)
+	rotated, err := os.ReadFile(filepath.Join(otherDir, "client.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certPath, rotated, 0600); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, certPath, time.Minute)
+
+	if serial := serialOf(t, c); serial != 1 {
+		t.Fatalf("expected previous certificate, got serial %d", serial)
+	}
+}
+
+func TestNewClientCertificateInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewClientCertificate(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key"))
+	if err == nil {
+		t.Error("expected an error for a missing key pair")
+	}
+}
@@ -5,6 +5,7 @@
 //
 //	mockgen -source=pkg/iservice.go -destination=internal/mock/service_mock.go -package=mock
 //
+
 // Package mock is a generated GoMock package.
 package mock
 
@@ -20,6 +21,7 @@ import (
 type MockIService struct {
 	ctrl     *gomock.Controller
 	recorder *MockIServiceMockRecorder
+	isgomock struct{}
 }
 
 // MockIServiceMockRecorder is the mock recorder for MockIService.
@@ -67,6 +69,21 @@ func (mr *MockIServiceMockRecorder) Init() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockIService)(nil).Init))
 }
 
+// ResolveAll mocks base method.
+func (m *MockIService) ResolveAll(ctx context.Context, evalCtx map[string]any) (map[string]openfeature.InterfaceResolutionDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAll", ctx, evalCtx)
+	ret0, _ := ret[0].(map[string]openfeature.InterfaceResolutionDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveAll indicates an expected call of ResolveAll.
+func (mr *MockIServiceMockRecorder) ResolveAll(ctx, evalCtx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAll", reflect.TypeOf((*MockIService)(nil).ResolveAll), ctx, evalCtx)
+}
+
 // ResolveBoolean mocks base method.
 func (m *MockIService) ResolveBoolean(ctx context.Context, key string, defaultValue bool, evalCtx map[string]any) openfeature.BoolResolutionDetail {
 	m.ctrl.T.Helper()
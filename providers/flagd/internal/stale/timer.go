@@ -0,0 +1,33 @@
+package stale
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer manages the stale connection timer with thread safety. The zero value is ready to use.
+type Timer struct {
+	timer *time.Timer
+	mu    sync.Mutex
+}
+
+// Start starts the stale timer, unless it is already running
+func (t *Timer) Start(duration time.Duration, callback func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timer == nil {
+		t.timer = time.AfterFunc(duration, callback)
+	}
+}
+
+// Stop stops the stale timer
+func (t *Timer) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
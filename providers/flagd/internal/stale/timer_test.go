@@ -0,0 +1,41 @@
+package stale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	var timer Timer
+	fired := make(chan struct{}, 2)
+
+	timer.Start(10*time.Millisecond, func() { fired <- struct{}{} })
+	// a running timer is not restarted
+	timer.Start(10*time.Millisecond, func() { fired <- struct{}{} })
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("expected the timer to fire")
+	}
+
+	select {
+	case <-fired:
+		t.Fatal("expected the timer to fire once")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTimerStop(t *testing.T) {
+	var timer Timer
+	fired := make(chan struct{}, 1)
+
+	timer.Start(10*time.Millisecond, func() { fired <- struct{}{} })
+	timer.Stop()
+
+	select {
+	case <-fired:
+		t.Fatal("expected the stopped timer not to fire")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
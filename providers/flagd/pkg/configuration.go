@@ -1,31 +1,56 @@
 package flagd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/go-logr/logr"
 	"github.com/open-feature/flagd/core/pkg/sync"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/logger"
+	process "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/in_process"
+	"go.opentelemetry.io/otel/metric"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ResolverType string
 
+// FlagSource is one of multiple chained flag sources of the in-process resolver
+type FlagSource = process.FlagSource
+
+// Operator is a custom JSONLogic operator of the in-process and file resolvers. It is called with the evaluated
+// arguments of the operator and the evaluation context, and returns the result of the operation.
+type Operator = process.Operator
+
+// ContextEnricher returns attributes added to the evaluation context of every evaluation, e.g. a tenant taken from ctx
+type ContextEnricher func(ctx context.Context) map[string]interface{}
+
+// HeaderProvider returns headers added to calls to flagd, e.g. an authorization header with a bearer token.
+// It is invoked for every call, so short-lived tokens can be refreshed.
+type HeaderProvider func(ctx context.Context) map[string]string
+
 // Naming and defaults must comply with flagd environment variables
 const (
-	defaultMaxCacheSize          int    = 1000
-	defaultRpcPort               uint16 = 8013
-	defaultInProcessPort         uint16 = 8015
-	defaultMaxEventStreamRetries        = 5
-	defaultTLS                   bool   = false
-	defaultCache                        = cache.LRUValue
-	defaultHost                         = "localhost"
-	defaultResolver                     = rpc
-	defaultGracePeriod                  = 5
+	defaultMaxCacheSize           int    = 1000
+	defaultCacheTTL                      = time.Minute
+	defaultRpcPort                uint16 = 8013
+	defaultInProcessPort          uint16 = 8015
+	defaultMaxEventStreamRetries         = 5
+	defaultTLS                    bool   = false
+	defaultCache                         = cache.LRUValue
+	defaultHost                          = "localhost"
+	defaultResolver                      = rpc
+	defaultGracePeriod                   = 5
+	defaultRetryBackoff                  = time.Second
+	defaultRetryBackoffMax               = 2 * time.Minute
+	defaultRetryBackoffMultiplier        = 2.0
+	defaultRetryBackoffJitter            = 0.0
+	defaultSnapshotDeadline              = 5 * time.Second
 
 	rpc       ResolverType = "rpc"
 	inProcess ResolverType = "in-process"
@@ -38,6 +63,8 @@ const (
 	flagdServerCertPathEnvironmentVariableName        = "FLAGD_SERVER_CERT_PATH"
 	flagdCacheEnvironmentVariableName                 = "FLAGD_CACHE"
 	flagdMaxCacheSizeEnvironmentVariableName          = "FLAGD_MAX_CACHE_SIZE"
+	flagdCacheTTLEnvironmentVariableName              = "FLAGD_CACHE_TTL"
+	flagdCacheTTLMsEnvironmentVariableName            = "FLAGD_CACHE_TTL_MS"
 	flagdMaxEventStreamRetriesEnvironmentVariableName = "FLAGD_MAX_EVENT_STREAM_RETRIES"
 	flagdResolverEnvironmentVariableName              = "FLAGD_RESOLVER"
 	flagdSourceProviderIDEnvironmentVariableName      = "FLAGD_PROVIDER_ID"
@@ -45,12 +72,27 @@ const (
 	flagdOfflinePathEnvironmentVariableName           = "FLAGD_OFFLINE_FLAG_SOURCE_PATH"
 	flagdTargetUriEnvironmentVariableName             = "FLAGD_TARGET_URI"
 	flagdGracePeriodVariableName                      = "FLAGD_RETRY_GRACE_PERIOD"
+	flagdRetryBackoffVariableName                     = "FLAGD_RETRY_BACKOFF_MS"
+	flagdRetryBackoffMaxVariableName                  = "FLAGD_RETRY_BACKOFF_MAX_MS"
+	flagdRetryBackoffMultiplierVariableName           = "FLAGD_RETRY_BACKOFF_MULTIPLIER"
+	flagdRetryBackoffJitterVariableName               = "FLAGD_RETRY_BACKOFF_JITTER"
+	flagdInfiniteEventStreamRetriesVariableName       = "FLAGD_INFINITE_EVENT_STREAM_RETRIES"
+	flagdFatalStatusCodesVariableName                 = "FLAGD_FATAL_STATUS_CODES"
+	flagdSnapshotPathVariableName                     = "FLAGD_SNAPSHOT_PATH"
+	flagdSnapshotDeadlineVariableName                 = "FLAGD_SNAPSHOT_DEADLINE_MS"
+	flagdDeadlineVariableName                         = "FLAGD_DEADLINE_MS"
+	flagdRequestTimeoutVariableName                   = "FLAGD_REQUEST_TIMEOUT_MS"
+	flagdClientCertPathVariableName                   = "FLAGD_CLIENT_CERT_PATH"
+	flagdClientKeyPathVariableName                    = "FLAGD_CLIENT_KEY_PATH"
 )
 
 type ProviderConfiguration struct {
 	Cache                            cache.Type
+	CacheTTL                         time.Duration
+	CacheMeterProvider               metric.MeterProvider
 	CertPath                         string
 	EventStreamConnectionMaxAttempts int
+	EventStreamInfiniteRetries       bool
 	Host                             string
 	MaxCacheSize                     int
 	OfflineFlagSourcePath            string
@@ -66,6 +108,27 @@ type ProviderConfiguration struct {
 	CustomSyncProviderUri            string
 	GrpcDialOptionsOverride          []grpc.DialOption
 	RetryGracePeriod                 int
+	RetryBackoff                     time.Duration
+	RetryBackoffMax                  time.Duration
+	RetryBackoffMultiplier           float64
+	RetryBackoffJitter               float64
+	FatalStatusCodes                 []string
+	SnapshotPath                     string
+	SnapshotDeadline                 time.Duration
+	FlagSources                      []FlagSource
+	Deadline                         time.Duration
+	RequestTimeout                   time.Duration
+	NonBlockingInit                  bool
+	ClientCertPath                   string
+	ClientKeyPath                    string
+	HeaderProvider                   HeaderProvider
+	InsecureHeaders                  bool
+	StaticContext                    map[string]interface{}
+	ContextEnrichers                 []ContextEnricher
+	Operators                        map[string]Operator
+	SharedStore                      string
+	CachePrefetch                    bool
+	CachePrefetchContext             map[string]interface{}
 
 	log logr.Logger
 }
@@ -73,6 +136,7 @@ type ProviderConfiguration struct {
 func newDefaultConfiguration(log logr.Logger) *ProviderConfiguration {
 	p := &ProviderConfiguration{
 		Cache:                            defaultCache,
+		CacheTTL:                         defaultCacheTTL,
 		EventStreamConnectionMaxAttempts: defaultMaxEventStreamRetries,
 		Host:                             defaultHost,
 		log:                              log,
@@ -80,6 +144,11 @@ func newDefaultConfiguration(log logr.Logger) *ProviderConfiguration {
 		Resolver:                         defaultResolver,
 		Tls:                              defaultTLS,
 		RetryGracePeriod:                 defaultGracePeriod,
+		RetryBackoff:                     defaultRetryBackoff,
+		RetryBackoffMax:                  defaultRetryBackoffMax,
+		RetryBackoffMultiplier:           defaultRetryBackoffMultiplier,
+		RetryBackoffJitter:               defaultRetryBackoffJitter,
+		SnapshotDeadline:                 defaultSnapshotDeadline,
 	}
 
 	p.updateFromEnvVar()
@@ -105,7 +174,7 @@ func NewProviderConfiguration(opts []ProviderOption) (*ProviderConfiguration, er
 }
 
 func configureProviderConfiguration(p *ProviderConfiguration) {
-	if len(p.OfflineFlagSourcePath) > 0 && p.Resolver == inProcess {
+	if len(p.OfflineFlagSourcePath) > 0 && p.Resolver == inProcess && len(p.FlagSources) == 0 {
 		p.Resolver = file
 	}
 
@@ -125,9 +194,61 @@ func validateProviderConfiguration(p *ProviderConfiguration) error {
 		return errors.New("resolver Type 'file' requires a OfflineFlagSourcePath")
 	}
 
+	if (p.ClientCertPath == "") != (p.ClientKeyPath == "") {
+		return errors.New("a client certificate requires both a certificate and a key path")
+	}
+
+	if len(p.FlagSources) > 0 && p.Resolver != inProcess {
+		return errors.New("flag sources require the resolver Type 'in-process'")
+	}
+
+	if len(p.Operators) > 0 && p.Resolver == rpc {
+		return errors.New("custom operators require the resolver Type 'in-process' or 'file'")
+	}
+
+	if p.CachePrefetch && p.Resolver != rpc {
+		return errors.New("cache prefetching requires the resolver Type 'rpc'")
+	}
+
+	if p.CachePrefetch && p.Cache == cache.ContextualValue {
+		// prefetched results would only be served to evaluations with exactly the prefetch context
+		return errors.New("cache prefetching is not supported with the contextual cache")
+	}
+
+	if p.SharedStore != "" && p.Resolver == rpc {
+		return errors.New("a shared store requires the resolver Type 'in-process' or 'file'")
+	}
+
+	// headers carry credentials, they are not sent over plaintext TCP connections unless explicitly allowed
+	plaintext := !p.Tls && ((p.Resolver == rpc && p.SocketPath == "") ||
+		(p.Resolver == inProcess && len(p.GrpcDialOptionsOverride) == 0))
+	if p.HeaderProvider != nil && plaintext && !p.InsecureHeaders {
+		return errors.New("a header provider requires TLS, use WithInsecureHeaders to send headers over plaintext")
+	}
+
+	for _, name := range p.FatalStatusCodes {
+		if _, err := normalizeStatusCode(name); err != nil {
+			return fmt.Errorf("invalid fatal status code '%s': %w", name, err)
+		}
+	}
+
+	if err := process.ValidateOperators(p.Operators); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// normalizeStatusCode upper-cases and trims a gRPC status code name, and checks that it names a status code
+func normalizeStatusCode(name string) (string, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	var code codes.Code
+	if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+		return name, err
+	}
+	return name, nil
+}
+
 // updateFromEnvVar is a utility to update configurations based on current environment variables
 func (cfg *ProviderConfiguration) updateFromEnvVar() {
 	portS := os.Getenv(flagdPortEnvironmentVariableName)
@@ -159,6 +280,14 @@ func (cfg *ProviderConfiguration) updateFromEnvVar() {
 		cfg.CertPath = certificatePath
 	}
 
+	if clientCertPath, clientKeyPath := os.Getenv(flagdClientCertPathVariableName),
+		os.Getenv(flagdClientKeyPathVariableName); clientCertPath != "" || clientKeyPath != "" {
+
+		cfg.Tls = true
+		cfg.ClientCertPath = clientCertPath
+		cfg.ClientKeyPath = clientKeyPath
+	}
+
 	if maxCacheSizeS := os.Getenv(flagdMaxCacheSizeEnvironmentVariableName); maxCacheSizeS != "" {
 		maxCacheSizeFromEnv, err := strconv.Atoi(maxCacheSizeS)
 		if err != nil {
@@ -177,6 +306,10 @@ func (cfg *ProviderConfiguration) updateFromEnvVar() {
 			cfg.Cache = cache.LRUValue
 		case cache.InMemValue:
 			cfg.Cache = cache.InMemValue
+		case cache.ContextualValue:
+			cfg.Cache = cache.ContextualValue
+		case cache.TTLValue:
+			cfg.Cache = cache.TTLValue
 		case cache.DisabledValue:
 			cfg.Cache = cache.DisabledValue
 		default:
@@ -185,6 +318,27 @@ func (cfg *ProviderConfiguration) updateFromEnvVar() {
 		}
 	}
 
+	// FLAGD_CACHE_TTL takes precedence over its alias FLAGD_CACHE_TTL_MS, both are in milliseconds
+	cacheTTLVariableName := flagdCacheTTLEnvironmentVariableName
+	cacheTTLS := os.Getenv(cacheTTLVariableName)
+	if cacheTTLS == "" {
+		cacheTTLVariableName = flagdCacheTTLMsEnvironmentVariableName
+		cacheTTLS = os.Getenv(cacheTTLVariableName)
+	}
+	if cacheTTLS != "" {
+		cacheTTL, err := strconv.Atoi(cacheTTLS)
+		if err == nil && cacheTTL <= 0 {
+			err = fmt.Errorf("non-positive time to live %d", cacheTTL)
+		}
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					cacheTTLVariableName, defaultCacheTTL))
+		} else {
+			cfg.CacheTTL = time.Duration(cacheTTL) * time.Millisecond
+		}
+	}
+
 	if maxEventStreamRetriesS := os.Getenv(
 		flagdMaxEventStreamRetriesEnvironmentVariableName); maxEventStreamRetriesS != "" {
 
@@ -198,6 +352,54 @@ func (cfg *ProviderConfiguration) updateFromEnvVar() {
 		}
 	}
 
+	if infiniteRetries := os.Getenv(flagdInfiniteEventStreamRetriesVariableName); infiniteRetries != "" {
+		cfg.EventStreamInfiniteRetries = strings.ToLower(infiniteRetries) == "true"
+	}
+
+	if retryBackoffS := os.Getenv(flagdRetryBackoffVariableName); retryBackoffS != "" {
+		retryBackoff, err := strconv.Atoi(retryBackoffS)
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdRetryBackoffVariableName, defaultRetryBackoff))
+		} else {
+			cfg.RetryBackoff = time.Duration(retryBackoff) * time.Millisecond
+		}
+	}
+
+	if retryBackoffMaxS := os.Getenv(flagdRetryBackoffMaxVariableName); retryBackoffMaxS != "" {
+		retryBackoffMax, err := strconv.Atoi(retryBackoffMaxS)
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdRetryBackoffMaxVariableName, defaultRetryBackoffMax))
+		} else {
+			cfg.RetryBackoffMax = time.Duration(retryBackoffMax) * time.Millisecond
+		}
+	}
+
+	if multiplierS := os.Getenv(flagdRetryBackoffMultiplierVariableName); multiplierS != "" {
+		multiplier, err := strconv.ParseFloat(multiplierS, 64)
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %v",
+					flagdRetryBackoffMultiplierVariableName, defaultRetryBackoffMultiplier))
+		} else {
+			cfg.RetryBackoffMultiplier = multiplier
+		}
+	}
+
+	if jitterS := os.Getenv(flagdRetryBackoffJitterVariableName); jitterS != "" {
+		jitter, err := strconv.ParseFloat(jitterS, 64)
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %v",
+					flagdRetryBackoffJitterVariableName, defaultRetryBackoffJitter))
+		} else {
+			cfg.RetryBackoffJitter = jitter
+		}
+	}
+
 	if resolver := os.Getenv(flagdResolverEnvironmentVariableName); resolver != "" {
 		switch strings.ToLower(resolver) {
 		case "rpc":
@@ -236,6 +438,55 @@ func (cfg *ProviderConfiguration) updateFromEnvVar() {
 		}
 	}
 
+	if fatalStatusCodes := os.Getenv(flagdFatalStatusCodesVariableName); fatalStatusCodes != "" {
+		cfg.FatalStatusCodes = nil
+		for _, name := range strings.Split(fatalStatusCodes, ",") {
+			name, err := normalizeStatusCode(name)
+			if err != nil {
+				cfg.log.Error(err, fmt.Sprintf("invalid fatal status code '%s' provided, ignoring it", name))
+				continue
+			}
+			cfg.FatalStatusCodes = append(cfg.FatalStatusCodes, name)
+		}
+	}
+
+	if snapshotPath := os.Getenv(flagdSnapshotPathVariableName); snapshotPath != "" {
+		cfg.SnapshotPath = snapshotPath
+	}
+
+	if snapshotDeadlineS := os.Getenv(flagdSnapshotDeadlineVariableName); snapshotDeadlineS != "" {
+		snapshotDeadline, err := strconv.Atoi(snapshotDeadlineS)
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdSnapshotDeadlineVariableName, defaultSnapshotDeadline))
+		} else {
+			cfg.SnapshotDeadline = time.Duration(snapshotDeadline) * time.Millisecond
+		}
+	}
+
+	if deadlineS := os.Getenv(flagdDeadlineVariableName); deadlineS != "" {
+		deadline, err := strconv.Atoi(deadlineS)
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, initialization is not bounded by a deadline",
+					flagdDeadlineVariableName))
+		} else {
+			cfg.Deadline = time.Duration(deadline) * time.Millisecond
+		}
+	}
+
+	if requestTimeoutS := os.Getenv(flagdRequestTimeoutVariableName); requestTimeoutS != "" {
+		requestTimeout, err := strconv.Atoi(requestTimeoutS)
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, evaluations are not bounded by a timeout",
+					flagdRequestTimeoutVariableName))
+		} else {
+			cfg.RequestTimeout = time.Duration(requestTimeout) * time.Millisecond
+		}
+	}
+
 }
 
 // ProviderOptions
@@ -258,6 +509,55 @@ func WithCertificatePath(path string) ProviderOption {
 	}
 }
 
+// WithClientCertificate enables mutual TLS, presenting the PEM encoded client certificate and key at the given paths to
+// flagd. The files are reloaded when they are rotated on disk.
+func WithClientCertificate(certPath string, keyPath string) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.ClientCertPath = certPath
+		p.ClientKeyPath = keyPath
+		p.Tls = true
+	}
+}
+
+// WithHeaderProvider adds the headers returned by the provider to every call to flagd. The rpc resolver adds them to
+// the evaluation and event stream calls, the in-process resolver to the flag sync stream as gRPC metadata.
+// The in-process resolver ignores the header provider if WithGrpcDialOptionsOverride is used.
+func WithHeaderProvider(provider HeaderProvider) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.HeaderProvider = provider
+	}
+}
+
+// WithInsecureHeaders allows the headers of the header provider to be sent over plaintext connections, e.g. to a local
+// sidecar terminating TLS. Without it, a header provider requires TLS.
+func WithInsecureHeaders() ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.InsecureHeaders = true
+	}
+}
+
+// WithStaticContext adds the attributes, e.g. the region or the version of the application, to the evaluation context
+// of every evaluation. Attributes of the evaluation context take precedence over them.
+func WithStaticContext(attributes map[string]interface{}) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		if p.StaticContext == nil {
+			p.StaticContext = make(map[string]interface{}, len(attributes))
+		}
+		for key, value := range attributes {
+			p.StaticContext[key] = value
+		}
+	}
+}
+
+// WithContextEnricher adds the attributes returned by the enricher to the evaluation context of every evaluation.
+// Enrichers are invoked in the order they are added, attributes of later enrichers take precedence over those of
+// earlier enrichers and over static context. Attributes of the evaluation context take precedence over all of them.
+func WithContextEnricher(enricher ContextEnricher) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.ContextEnrichers = append(p.ContextEnrichers, enricher)
+	}
+}
+
 // WithPort specifies the port of the flagd server. Defaults to 8013
 func WithPort(port uint16) ProviderOption {
 	return func(p *ProviderConfiguration) {
@@ -305,6 +605,39 @@ func WithLRUCache(size int) ProviderOption {
 	}
 }
 
+// WithContextualCache applies least recently used caching keyed on the flag key and the evaluation context.
+// Besides static evaluations, results with reason TARGETING_MATCH are cached for the context they were resolved with.
+// The provided size is the limit of the number of cached values across all contexts.
+func WithContextualCache(size int) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		if size > 0 {
+			p.MaxCacheSize = size
+		}
+		p.Cache = cache.ContextualValue
+	}
+}
+
+// WithTTLCache applies a cache in which every entry expires after the provided time to live, regardless of events
+// received from flagd. The provided size limits the number of cached values, a size of zero or less removes the limit.
+// Once the limit is reached each new entry replaces the oldest entry.
+func WithTTLCache(size int, ttl time.Duration) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.MaxCacheSize = size
+		if ttl > 0 {
+			p.CacheTTL = ttl
+		}
+		p.Cache = cache.TTLValue
+	}
+}
+
+// WithCacheMetrics reports the hit, miss, eviction and purge counts of the cache as OpenTelemetry counters
+// through the provided meter provider. Use otel.GetMeterProvider() to report through the global meter provider.
+func WithCacheMetrics(provider metric.MeterProvider) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.CacheMeterProvider = provider
+	}
+}
+
 // WithEventStreamConnectionMaxAttempts sets the maximum number of attempts to connect to flagd's event stream.
 // On successful connection the attempts are reset.
 func WithEventStreamConnectionMaxAttempts(i int) ProviderOption {
@@ -313,6 +646,38 @@ func WithEventStreamConnectionMaxAttempts(i int) ProviderOption {
 	}
 }
 
+// WithEventStreamInfiniteRetries retries connecting to flagd's event stream without a limit of attempts.
+// Initialization then only completes once the event stream is connected.
+func WithEventStreamInfiniteRetries() ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.EventStreamInfiniteRetries = true
+	}
+}
+
+// WithRetryBackoff sets the initial and the maximum delay between attempts to connect to flagd's event stream.
+// The delay grows exponentially from the initial delay up to the maximum delay.
+func WithRetryBackoff(initial time.Duration, maximum time.Duration) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.RetryBackoff = initial
+		p.RetryBackoffMax = maximum
+	}
+}
+
+// WithRetryBackoffMultiplier sets the factor applied to the delay between attempts to connect to flagd's event stream.
+func WithRetryBackoffMultiplier(multiplier float64) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.RetryBackoffMultiplier = multiplier
+	}
+}
+
+// WithRetryBackoffJitter randomizes the delay between attempts to connect to flagd's event stream by the given
+// fraction (between 0 and 1) of the delay, in either direction. This avoids reconnection storms of many clients.
+func WithRetryBackoffJitter(jitter float64) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.RetryBackoffJitter = jitter
+	}
+}
+
 // WithLogger sets the logger used by the provider.
 func WithLogger(l logr.Logger) ProviderOption {
 	return func(p *ProviderConfiguration) {
@@ -415,3 +780,129 @@ func WithRetryGracePeriod(gracePeriod int) ProviderOption {
 		p.RetryGracePeriod = gracePeriod
 	}
 }
+
+// WithFatalStatusCodes sets the gRPC status codes (e.g. "UNAUTHENTICATED", "PERMISSION_DENIED") on which the
+// in-process sync stops retrying and the provider transitions to the fatal state. The names are case-insensitive,
+// unknown names are rejected when the provider is created.
+func WithFatalStatusCodes(statusCodes ...string) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.FatalStatusCodes = make([]string, 0, len(statusCodes))
+		for _, name := range statusCodes {
+			normalized, _ := normalizeStatusCode(name)
+			p.FatalStatusCodes = append(p.FatalStatusCodes, normalized)
+		}
+	}
+}
+
+// WithSnapshotPath persists every successfully applied flag configuration of the in-process resolver to the given
+// file. If the sync is not ready within the snapshot deadline at startup, the provider is initialized from this
+// snapshot and reports the stale state until the sync catches up.
+func WithSnapshotPath(path string) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.SnapshotPath = path
+	}
+}
+
+// WithSnapshotDeadline sets the time to wait for the sync at startup before initializing from the snapshot
+func WithSnapshotDeadline(deadline time.Duration) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.SnapshotDeadline = deadline
+	}
+}
+
+// WithFlagSources chains multiple flag sources, which are merged into a single flag configuration.
+// Flags of later sources take precedence over flags with the same key of earlier sources, e.g. a file with local
+// overrides should follow the gRPC source. The sources replace the single source selected by the other options.
+// This is only useful with inProcess resolver type
+func WithFlagSources(sources ...FlagSource) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.FlagSources = sources
+	}
+}
+
+// FileSource is a flag source reading flags from the file at path, which is watched for changes
+func FileSource(path string) FlagSource {
+	return FlagSource{Type: process.SourceFile, Path: path}
+}
+
+// GrpcSource is a flag source syncing flags from flagd, using the connection options of the provider
+func GrpcSource() FlagSource {
+	return FlagSource{Type: process.SourceGrpc}
+}
+
+// CustomSource is a flag source obtaining flags from a custom sync provider.
+// The sync provider must use uri as source of the data it sends.
+func CustomSource(syncProvider sync.ISync, uri string) FlagSource {
+	return FlagSource{Type: process.SourceCustom, SyncProvider: syncProvider, Uri: uri}
+}
+
+// WithDeadline bounds the time Init waits for the provider to become ready. Once the deadline is exceeded, Init fails
+// with a PROVIDER_NOT_READY error while initialization continues in the background, a PROVIDER_READY event is
+// emitted once it completes. Initialization is not bounded by default.
+func WithDeadline(deadline time.Duration) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.Deadline = deadline
+	}
+}
+
+// WithRequestTimeout bounds flag evaluations of the rpc resolver whose context has no deadline, so an unresponsive
+// flagd does not block evaluations indefinitely. Timed out evaluations return the default value with the TIMEOUT reason
+// and a GENERAL error wrapping ErrRequestTimeout: the OpenFeature SDK has no timeout error code, and resolution errors
+// can only be created with its standard codes. Evaluations are not bounded by default.
+func WithRequestTimeout(timeout time.Duration) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.RequestTimeout = timeout
+	}
+}
+
+// WithNonBlockingInit makes Init return immediately, initialization completes in the background.
+// Until then, evaluations return the default value with a PROVIDER_NOT_READY error. A PROVIDER_READY event is emitted
+// once the provider is ready, or a PROVIDER_ERROR event if initialization failed.
+func WithNonBlockingInit() ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.NonBlockingInit = true
+	}
+}
+
+// WithOperator registers a custom JSONLogic operator, which targeting rules use like builtin operators, e.g.
+// {"cidr": [{"var": "ip"}, "10.0.0.0/8"]}. Operators can not replace the builtin operators of JSONLogic and flagd.
+// Operators are registered process-wide, so a name can only be used by a single function: providers registering
+// another function under the name of an already registered operator fail validation.
+// This is only useful with inProcess and file resolver types
+func WithOperator(name string, operator Operator) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		if p.Operators == nil {
+			p.Operators = map[string]Operator{}
+		}
+		p.Operators[name] = operator
+	}
+}
+
+// WithSharedStore shares the sync connection and the flag store with the other providers configured with the same
+// name, e.g. providers registered for several domains. The shared store is created with the configuration of the
+// first provider to be initialized, and closed once all providers sharing it are shut down.
+// Only providers with the same selector share a flag store, as the flag store can not hold the flags of several
+// selectors: providers with different selectors only share the gRPC connection, every selector has its own sync stream
+// and flag store. Providers sharing a store must sync from the same flag sources with the same connection settings,
+// including the header provider and dial options, and providers of the same selector must configure the same fatal
+// status codes, retry grace period, snapshot and custom operators.
+// Evaluation options such as context enrichment remain specific to each provider.
+// This is only useful with inProcess and file resolver types
+func WithSharedStore(name string) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.SharedStore = name
+	}
+}
+
+// WithCachePrefetch warms the cache with a bulk evaluation of all flags whenever the provider becomes ready and
+// whenever flags change, so evaluations after a change do not wait for flagd. Flags are evaluated for the static
+// context merged with evalCtx, only flags with reason STATIC are cached. Numbers are only cached for float evaluations,
+// as bulk evaluations do not distinguish integers. The bulk evaluation is bounded by the request timeout, or by 5
+// seconds without request timeout.
+// This is only useful with the rpc resolver type and an enabled cache, the contextual cache is not supported
+func WithCachePrefetch(evalCtx map[string]interface{}) ProviderOption {
+	return func(p *ProviderConfiguration) {
+		p.CachePrefetch = true
+		p.CachePrefetchContext = evalCtx
+	}
+}
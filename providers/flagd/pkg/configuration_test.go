@@ -1,7 +1,12 @@
 package flagd
 
 import (
+	"context"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
 )
 
 func TestConfigureProviderConfigurationInProcessWithOfflineFile(t *testing.T) {
@@ -64,3 +69,340 @@ func TestValidateProviderConfigurationFileMissingData(t *testing.T) {
 		t.Errorf("Error expected but check succeeded")
 	}
 }
+
+func TestUpdateFromEnvVarCacheTTL(t *testing.T) {
+	t.Setenv(flagdCacheEnvironmentVariableName, "ttl")
+	t.Setenv(flagdCacheTTLEnvironmentVariableName, "30000")
+
+	// given
+	providerConfiguration, err := NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if providerConfiguration.Cache != cache.TTLValue {
+		t.Errorf("incorrect Cache, expected %v, got %v", cache.TTLValue, providerConfiguration.Cache)
+	}
+
+	if providerConfiguration.CacheTTL != 30*time.Second {
+		t.Errorf("incorrect CacheTTL, expected %v, got %v", 30*time.Second, providerConfiguration.CacheTTL)
+	}
+
+	// the alias is only used if the variable is not set
+	t.Setenv(flagdCacheTTLMsEnvironmentVariableName, "10000")
+	providerConfiguration, err = NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if providerConfiguration.CacheTTL != 30*time.Second {
+		t.Errorf("incorrect CacheTTL, expected %v, got %v", 30*time.Second, providerConfiguration.CacheTTL)
+	}
+
+	t.Setenv(flagdCacheTTLEnvironmentVariableName, "")
+	providerConfiguration, err = NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if providerConfiguration.CacheTTL != 10*time.Second {
+		t.Errorf("incorrect CacheTTL, expected %v, got %v", 10*time.Second, providerConfiguration.CacheTTL)
+	}
+}
+
+func TestUpdateFromEnvVarRetryBackoff(t *testing.T) {
+	t.Setenv(flagdRetryBackoffVariableName, "500")
+	t.Setenv(flagdRetryBackoffMaxVariableName, "10000")
+	t.Setenv(flagdRetryBackoffMultiplierVariableName, "1.5")
+	t.Setenv(flagdRetryBackoffJitterVariableName, "0.2")
+	t.Setenv(flagdInfiniteEventStreamRetriesVariableName, "true")
+
+	// given
+	providerConfiguration, err := NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if providerConfiguration.RetryBackoff != 500*time.Millisecond {
+		t.Errorf("incorrect RetryBackoff, expected %v, got %v", 500*time.Millisecond, providerConfiguration.RetryBackoff)
+	}
+
+	if providerConfiguration.RetryBackoffMax != 10*time.Second {
+		t.Errorf("incorrect RetryBackoffMax, expected %v, got %v", 10*time.Second, providerConfiguration.RetryBackoffMax)
+	}
+
+	if providerConfiguration.RetryBackoffMultiplier != 1.5 {
+		t.Errorf("incorrect RetryBackoffMultiplier, expected %v, got %v", 1.5, providerConfiguration.RetryBackoffMultiplier)
+	}
+
+	if providerConfiguration.RetryBackoffJitter != 0.2 {
+		t.Errorf("incorrect RetryBackoffJitter, expected %v, got %v", 0.2, providerConfiguration.RetryBackoffJitter)
+	}
+
+	if !providerConfiguration.EventStreamInfiniteRetries {
+		t.Errorf("expected EventStreamInfiniteRetries to be enabled")
+	}
+}
+
+func TestUpdateFromEnvVarFatalStatusCodes(t *testing.T) {
+	t.Setenv(flagdFatalStatusCodesVariableName, "UNAUTHENTICATED, permission_denied,NOT_A_CODE")
+
+	// given
+	providerConfiguration, err := NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	expected := []string{"UNAUTHENTICATED", "PERMISSION_DENIED"}
+	if !reflect.DeepEqual(providerConfiguration.FatalStatusCodes, expected) {
+		t.Errorf("incorrect FatalStatusCodes, expected %v, got %v", expected, providerConfiguration.FatalStatusCodes)
+	}
+}
+
+func TestFatalStatusCodesOption(t *testing.T) {
+	providerConfiguration, err := NewProviderConfiguration([]ProviderOption{
+		WithInProcessResolver(),
+		WithFatalStatusCodes("unauthenticated", " Permission_Denied "),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"UNAUTHENTICATED", "PERMISSION_DENIED"}
+	if !reflect.DeepEqual(providerConfiguration.FatalStatusCodes, expected) {
+		t.Errorf("incorrect FatalStatusCodes, expected %v, got %v", expected, providerConfiguration.FatalStatusCodes)
+	}
+
+	_, err = NewProviderConfiguration([]ProviderOption{
+		WithInProcessResolver(),
+		WithFatalStatusCodes("NOT_A_CODE"),
+	})
+	if err == nil {
+		t.Error("expected an unknown fatal status code to be rejected")
+	}
+}
+
+func TestUpdateFromEnvVarSnapshot(t *testing.T) {
+	t.Setenv(flagdSnapshotPathVariableName, "/tmp/flags.json")
+	t.Setenv(flagdSnapshotDeadlineVariableName, "250")
+
+	// given
+	providerConfiguration, err := NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if providerConfiguration.SnapshotPath != "/tmp/flags.json" {
+		t.Errorf("incorrect SnapshotPath, expected %v, got %v", "/tmp/flags.json", providerConfiguration.SnapshotPath)
+	}
+
+	if providerConfiguration.SnapshotDeadline != 250*time.Millisecond {
+		t.Errorf("incorrect SnapshotDeadline, expected %v, got %v", 250*time.Millisecond, providerConfiguration.SnapshotDeadline)
+	}
+}
+
+func TestConfigureFlagSources(t *testing.T) {
+	// given
+	providerConfiguration, err := NewProviderConfiguration([]ProviderOption{
+		WithInProcessResolver(),
+		WithOfflineFilePath("/flags/base.json"),
+		WithFlagSources(GrpcSource(), FileSource("/flags/overrides.json")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if providerConfiguration.Resolver != inProcess {
+		t.Errorf("incorrect Resolver, expected %v, got %v", inProcess, providerConfiguration.Resolver)
+	}
+
+	expected := []FlagSource{GrpcSource(), FileSource("/flags/overrides.json")}
+	if !reflect.DeepEqual(providerConfiguration.FlagSources, expected) {
+		t.Errorf("incorrect FlagSources, expected %v, got %v", expected, providerConfiguration.FlagSources)
+	}
+}
+
+func TestValidateFlagSourcesRequireInProcessResolver(t *testing.T) {
+	_, err := NewProviderConfiguration([]ProviderOption{
+		WithRPCResolver(),
+		WithFlagSources(GrpcSource()),
+	})
+	if err == nil {
+		t.Error("expected flag sources to be rejected with the rpc resolver")
+	}
+}
+
+func TestUpdateFromEnvVarDeadline(t *testing.T) {
+	t.Setenv(flagdDeadlineVariableName, "500")
+
+	// given
+	providerConfiguration, err := NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if providerConfiguration.Deadline != 500*time.Millisecond {
+		t.Errorf("incorrect Deadline, expected %v, got %v", 500*time.Millisecond, providerConfiguration.Deadline)
+	}
+}
+
+func TestHeaderProviderRequiresTLS(t *testing.T) {
+	headers := WithHeaderProvider(func(context.Context) map[string]string {
+		return map[string]string{"Authorization": "Bearer token"}
+	})
+
+	for name, options := range map[string][]ProviderOption{
+		"rpc":        {WithRPCResolver(), headers},
+		"in-process": {WithInProcessResolver(), headers},
+	} {
+		if _, err := NewProviderConfiguration(options); err == nil {
+			t.Errorf("expected headers over plaintext to be rejected with the %s resolver", name)
+		}
+		if _, err := NewProviderConfiguration(append(options, WithTLS(""))); err != nil {
+			t.Errorf("expected headers over TLS to be accepted with the %s resolver: %v", name, err)
+		}
+		if _, err := NewProviderConfiguration(append(options, WithInsecureHeaders())); err != nil {
+			t.Errorf("expected insecure headers to be accepted with the %s resolver: %v", name, err)
+		}
+	}
+}
+
+func TestUpdateFromEnvVarRequestTimeout(t *testing.T) {
+	t.Setenv(flagdRequestTimeoutVariableName, "200")
+
+	// given
+	providerConfiguration, err := NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if providerConfiguration.RequestTimeout != 200*time.Millisecond {
+		t.Errorf("incorrect RequestTimeout, expected %v, got %v", 200*time.Millisecond, providerConfiguration.RequestTimeout)
+	}
+
+	// options take precedence over env variables
+	providerConfiguration, err = NewProviderConfiguration([]ProviderOption{WithRequestTimeout(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if providerConfiguration.RequestTimeout != time.Second {
+		t.Errorf("incorrect RequestTimeout, expected %v, got %v", time.Second, providerConfiguration.RequestTimeout)
+	}
+}
+
+func TestUpdateFromEnvVarClientCertificate(t *testing.T) {
+	t.Setenv(flagdClientCertPathVariableName, "/certs/client.crt")
+	t.Setenv(flagdClientKeyPathVariableName, "/certs/client.key")
+
+	// given
+	providerConfiguration, err := NewProviderConfiguration(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if !providerConfiguration.Tls {
+		t.Errorf("expected a client certificate to enable TLS")
+	}
+
+	if providerConfiguration.ClientCertPath != "/certs/client.crt" {
+		t.Errorf("incorrect ClientCertPath, expected %v, got %v", "/certs/client.crt", providerConfiguration.ClientCertPath)
+	}
+
+	if providerConfiguration.ClientKeyPath != "/certs/client.key" {
+		t.Errorf("incorrect ClientKeyPath, expected %v, got %v", "/certs/client.key", providerConfiguration.ClientKeyPath)
+	}
+}
+
+func TestValidateClientCertificateRequiresKey(t *testing.T) {
+	_, err := NewProviderConfiguration([]ProviderOption{
+		WithClientCertificate("/certs/client.crt", ""),
+	})
+	if err == nil {
+		t.Error("expected a client certificate without key to be rejected")
+	}
+}
+
+func TestValidateOperators(t *testing.T) {
+	operator := func(args []interface{}, evalCtx map[string]interface{}) interface{} { return true }
+
+	tests := map[string]struct {
+		options   []ProviderOption
+		expectErr bool
+	}{
+		"custom operator": {
+			options: []ProviderOption{WithInProcessResolver(), WithOperator("cidr", operator)},
+		},
+		"builtin operator": {
+			options:   []ProviderOption{WithInProcessResolver(), WithOperator("sem_ver", operator)},
+			expectErr: true,
+		},
+		"rpc resolver": {
+			options:   []ProviderOption{WithRPCResolver(), WithOperator("cidr", operator)},
+			expectErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewProviderConfiguration(test.options)
+			if (err != nil) != test.expectErr {
+				t.Errorf("expected error %v, got %v", test.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateSharedStoreRequiresLocalResolver(t *testing.T) {
+	_, err := NewProviderConfiguration([]ProviderOption{
+		WithRPCResolver(),
+		WithSharedStore("shared"),
+	})
+	if err == nil {
+		t.Error("expected a shared store to be rejected with the rpc resolver")
+	}
+}
+
+func TestConfigureCachePrefetch(t *testing.T) {
+	// given
+	providerConfiguration, err := NewProviderConfiguration([]ProviderOption{
+		WithStaticContext(map[string]interface{}{"region": "eu-west-1", "stage": "prod"}),
+		WithCachePrefetch(map[string]interface{}{"stage": "canary"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if !providerConfiguration.CachePrefetch {
+		t.Error("expected cache prefetching to be enabled")
+	}
+
+	// the prefetch context takes precedence over static context
+	expected := map[string]interface{}{"region": "eu-west-1", "stage": "canary"}
+	if evalCtx := prefetchContext(providerConfiguration); !reflect.DeepEqual(evalCtx, expected) {
+		t.Errorf("incorrect prefetch context, expected %v, got %v", expected, evalCtx)
+	}
+
+	// prefetching requires the rpc resolver
+	_, err = NewProviderConfiguration([]ProviderOption{
+		WithInProcessResolver(),
+		WithCachePrefetch(nil),
+	})
+	if err == nil {
+		t.Error("expected cache prefetching to be rejected with the in-process resolver")
+	}
+	// prefetched results of the contextual cache would only be served to the prefetch context
+	_, err = NewProviderConfiguration([]ProviderOption{
+		WithContextualCache(100),
+		WithCachePrefetch(nil),
+	})
+	if err == nil {
+		t.Error("expected cache prefetching to be rejected with the contextual cache")
+	}
+}
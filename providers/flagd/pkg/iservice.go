@@ -20,5 +20,6 @@ type IService interface {
 		evalCtx map[string]interface{}) of.IntResolutionDetail
 	ResolveObject(ctx context.Context, key string, defaultValue interface{},
 		evalCtx map[string]interface{}) of.InterfaceResolutionDetail
+	ResolveAll(ctx context.Context, evalCtx map[string]interface{}) (map[string]of.InterfaceResolutionDetail, error)
 	EventChannel() <-chan of.Event
 }
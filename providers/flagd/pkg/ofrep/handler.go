@@ -0,0 +1,160 @@
+// Package ofrep serves flag evaluations of a flagd provider over the OpenFeature Remote Evaluation Protocol (OFREP).
+package ofrep
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/open-feature/flagd/core/pkg/model"
+	"github.com/open-feature/flagd/core/pkg/service/ofrep"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	// BulkEvaluationPath is the path of the bulk evaluation endpoint
+	BulkEvaluationPath = "/ofrep/v1/evaluate/flags"
+	// FlagEvaluationPath is the path of the single flag evaluation endpoint, with the flag key as last segment
+	FlagEvaluationPath = "/ofrep/v1/evaluate/flags/{key}"
+)
+
+// Resolver evaluates single flags regardless of their type, and all flags at once. It is implemented by the flagd
+// Provider.
+type Resolver interface {
+	ResolveFlag(ctx context.Context, flagKey string, evalCtx of.FlattenedContext) of.InterfaceResolutionDetail
+	ResolveAll(ctx context.Context, evalCtx of.FlattenedContext) (map[string]of.InterfaceResolutionDetail, error)
+}
+
+type handler struct {
+	resolver Resolver
+}
+
+// NewHandler returns a handler serving the OFREP single flag and bulk evaluation endpoints, backed by the resolver.
+// The resolver is meant to be an initialized flagd Provider using the in-process or file resolver, which evaluates
+// flags locally. The handler can be mounted on any server, it routes on the full OFREP paths.
+func NewHandler(resolver Resolver) http.Handler {
+	h := &handler{resolver: resolver}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+BulkEvaluationPath, h.evaluateFlags)
+	mux.HandleFunc("POST "+FlagEvaluationPath, h.evaluateFlag)
+	return mux
+}
+
+// evaluateFlags evaluates all flags. Responses carry an ETag, so that clients polling with If-None-Match are only
+// sent changed evaluations.
+func (h *handler) evaluateFlags(w http.ResponseWriter, r *http.Request) {
+	evalCtx, err := readContext(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ofrep.BulkEvaluationContextErrorFrom(model.InvalidContextCode, err.Error()))
+		return
+	}
+
+	details, err := h.resolver.ResolveAll(r.Context(), evalCtx)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, ofrep.InternalError{ErrorDetails: err.Error()})
+		return
+	}
+
+	keys := make([]string, 0, len(details))
+	for key := range details {
+		keys = append(keys, key)
+	}
+	// a stable order keeps the ETag stable
+	sort.Strings(keys)
+
+	response := ofrep.BulkEvaluationResponse{
+		Flags:    make([]interface{}, 0, len(keys)),
+		Metadata: model.Metadata{},
+	}
+	for _, key := range keys {
+		_, evaluation := evaluationFrom(key, details[key])
+		response.Flags = append(response.Flags, evaluation)
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, ofrep.InternalError{ErrorDetails: err.Error()})
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// evaluateFlag evaluates the flag of the path. Disabled flags are reported as not found, as done by flagd.
+func (h *handler) evaluateFlag(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+
+	evalCtx, err := readContext(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ofrep.ContextErrorResponseFrom(key))
+		return
+	}
+
+	status, evaluation := evaluationFrom(key, h.resolver.ResolveFlag(r.Context(), key, evalCtx))
+	writeJSON(w, status, evaluation)
+}
+
+// evaluationFrom converts the resolution detail to an OFREP evaluation and its status code
+func evaluationFrom(key string, detail of.InterfaceResolutionDetail) (int, interface{}) {
+	resolution := detail.ResolutionDetail()
+	if resolution.ErrorCode != "" {
+		status := http.StatusBadRequest
+		if resolution.ErrorCode == of.FlagNotFoundCode {
+			status = http.StatusNotFound
+		}
+		return status, ofrep.EvaluationError{
+			Key:          key,
+			ErrorCode:    string(resolution.ErrorCode),
+			ErrorDetails: resolution.ErrorMessage,
+			Metadata:     model.Metadata(detail.FlagMetadata),
+		}
+	}
+
+	return http.StatusOK, ofrep.EvaluationSuccess{
+		Value:    detail.Value,
+		Key:      key,
+		Reason:   string(detail.Reason),
+		Variant:  detail.Variant,
+		Metadata: model.Metadata(detail.FlagMetadata),
+	}
+}
+
+// readContext reads the evaluation context of the request. An empty body or a missing context is an empty context.
+func readContext(r *http.Request) (of.FlattenedContext, error) {
+	var request ofrep.Request
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	switch evalCtx := request.Context.(type) {
+	case nil:
+		return of.FlattenedContext{}, nil
+	case map[string]interface{}:
+		return evalCtx, nil
+	default:
+		return nil, errors.New("context must be an object")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
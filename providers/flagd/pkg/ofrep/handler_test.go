@@ -0,0 +1,213 @@
+package ofrep
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	flagd "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+const flags = `{
+	"flags": {
+		"boolFlag": {
+			"state": "ENABLED",
+			"variants": {"on": true, "off": false},
+			"defaultVariant": "off",
+			"targeting": {"if": [{"==": [{"var": "email"}, "user@example.com"]}, "on", null]}
+		},
+		"stringFlag": {
+			"state": "ENABLED",
+			"variants": {"a": "alpha", "b": "beta"},
+			"defaultVariant": "a"
+		},
+		"disabledFlag": {
+			"state": "DISABLED",
+			"variants": {"on": true, "off": false},
+			"defaultVariant": "on"
+		}
+	}
+}`
+
+func newTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "flags.json")
+	if err := os.WriteFile(path, []byte(flags), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := flagd.NewProvider(flagd.WithFileResolver(), flagd.WithOfflineFilePath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Init(of.EvaluationContext{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	return NewHandler(provider)
+}
+
+func post(t *testing.T, handler http.Handler, path string, body string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEvaluateFlag(t *testing.T) {
+	handler := newTestHandler(t)
+
+	tests := map[string]struct {
+		path         string
+		body         string
+		expectStatus int
+		expect       map[string]interface{}
+	}{
+		"targeting match": {
+			path:         "/ofrep/v1/evaluate/flags/boolFlag",
+			body:         `{"context": {"email": "user@example.com"}}`,
+			expectStatus: http.StatusOK,
+			expect:       map[string]interface{}{"key": "boolFlag", "value": true, "variant": "on", "reason": "TARGETING_MATCH"},
+		},
+		"without body": {
+			path:         "/ofrep/v1/evaluate/flags/stringFlag",
+			expectStatus: http.StatusOK,
+			expect:       map[string]interface{}{"key": "stringFlag", "value": "alpha", "variant": "a", "reason": "STATIC"},
+		},
+		"disabled flag": {
+			path:         "/ofrep/v1/evaluate/flags/disabledFlag",
+			body:         `{"context": {}}`,
+			expectStatus: http.StatusNotFound,
+			expect:       map[string]interface{}{"key": "disabledFlag", "errorCode": "FLAG_NOT_FOUND"},
+		},
+		"missing flag": {
+			path:         "/ofrep/v1/evaluate/flags/missingFlag",
+			body:         `{"context": {}}`,
+			expectStatus: http.StatusNotFound,
+			expect:       map[string]interface{}{"key": "missingFlag", "errorCode": "FLAG_NOT_FOUND"},
+		},
+		"invalid context": {
+			path:         "/ofrep/v1/evaluate/flags/boolFlag",
+			body:         `{"context": "user@example.com"}`,
+			expectStatus: http.StatusBadRequest,
+			expect:       map[string]interface{}{"key": "boolFlag", "errorCode": "INVALID_CONTEXT"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := post(t, handler, test.path, test.body, nil)
+
+			if rec.Code != test.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", test.expectStatus, rec.Code, rec.Body.String())
+			}
+
+			var response map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			for key, value := range test.expect {
+				if response[key] != value {
+					t.Errorf("expected %s to be %v, got %v", key, value, response[key])
+				}
+			}
+		})
+	}
+}
+
+// singleFlagResolver resolves single flags only, bulk evaluations fail the test
+type singleFlagResolver struct {
+	t *testing.T
+}
+
+func (r singleFlagResolver) ResolveFlag(_ context.Context, flagKey string, _ of.FlattenedContext) of.InterfaceResolutionDetail {
+	return of.InterfaceResolutionDetail{Value: flagKey, ProviderResolutionDetail: of.ProviderResolutionDetail{Reason: of.StaticReason}}
+}
+
+func (r singleFlagResolver) ResolveAll(context.Context, of.FlattenedContext) (map[string]of.InterfaceResolutionDetail, error) {
+	r.t.Error("expected the single flag endpoint not to evaluate all flags")
+	return nil, nil
+}
+
+func TestEvaluateFlagResolvesSingleFlag(t *testing.T) {
+	rec := post(t, NewHandler(singleFlagResolver{t: t}), "/ofrep/v1/evaluate/flags/stringFlag", `{}`, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var evaluation map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &evaluation); err != nil {
+		t.Fatal(err)
+	}
+	if evaluation["value"] != "stringFlag" {
+		t.Errorf("expected the value of the single flag resolution, got %v", evaluation)
+	}
+}
+
+func TestEvaluateFlags(t *testing.T) {
+	handler := newTestHandler(t)
+
+	rec := post(t, handler, "/ofrep/v1/evaluate/flags", `{"context": {"email": "user@example.com"}}`, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var response struct {
+		Flags []map[string]interface{} `json:"flags"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]interface{}{}
+	for _, flag := range response.Flags {
+		values[flag["key"].(string)] = flag["value"]
+	}
+	expected := map[string]interface{}{"boolFlag": true, "stringFlag": "alpha"}
+	if len(values) != len(expected) || values["boolFlag"] != true || values["stringFlag"] != "alpha" {
+		t.Errorf("expected evaluations %v, got %v", expected, values)
+	}
+
+	// unchanged evaluations are not sent again
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	rec = post(t, handler, "/ofrep/v1/evaluate/flags", `{"context": {"email": "user@example.com"}}`,
+		http.Header{"If-None-Match": []string{etag}})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected status %d, got %d", http.StatusNotModified, rec.Code)
+	}
+
+	// evaluations for another context differ
+	rec = post(t, handler, "/ofrep/v1/evaluate/flags", `{"context": {}}`, http.Header{"If-None-Match": []string{etag}})
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestEvaluateFlagsMethodNotAllowed(t *testing.T) {
+	handler := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/ofrep/v1/evaluate/flags", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
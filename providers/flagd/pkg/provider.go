@@ -2,7 +2,11 @@ package flagd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"sync/atomic"
+	"time"
 
 	parallel "sync"
 
@@ -10,18 +14,34 @@ import (
 	process "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/in_process"
 	rpcService "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/rpc"
 	of "github.com/open-feature/go-sdk/openfeature"
+	"go.opentelemetry.io/otel/metric"
 )
 
 const (
 	defaultCustomSyncProviderUri = "syncprovider://custom"
+
+	// ReasonTimeout is the reason of evaluations exceeding their deadline, which have the GENERAL error code as the SDK
+	// has no timeout error code
+	ReasonTimeout = rpcService.ReasonTimeout
 )
 
+// ErrRequestTimeout is wrapped by the resolution errors of evaluations exceeding the request timeout
+var ErrRequestTimeout = rpcService.ErrRequestTimeout
+
+// CacheStats is a snapshot of the flag evaluation cache statistics
+type CacheStats = cache.Stats
+
 type Provider struct {
 	initialized           bool
+	initializing          bool
 	providerConfiguration *ProviderConfiguration
 	service               IService
-	status                of.State
-	mtx                   parallel.RWMutex
+	cache                 *cache.Service
+	cacheMetrics          metric.Registration
+	// status is read on every evaluation, it is not guarded by mtx so evaluations never wait for the lifecycle
+	status atomic.Value
+	// mtx guards the lifecycle, it is not held while waiting for the initialization
+	mtx parallel.RWMutex
 
 	eventStream chan of.Event
 }
@@ -37,13 +57,22 @@ func NewProvider(opts ...ProviderOption) (*Provider, error) {
 		initialized:           false,
 		eventStream:           make(chan of.Event),
 		providerConfiguration: providerConfiguration,
-		status:                of.NotReadyState,
 	}
+	provider.status.Store(of.NotReadyState)
 
 	cacheService := cache.NewCacheService(
 		provider.providerConfiguration.Cache,
 		provider.providerConfiguration.MaxCacheSize,
+		provider.providerConfiguration.CacheTTL,
 		provider.providerConfiguration.log)
+	provider.cache = cacheService
+
+	if provider.providerConfiguration.CacheMeterProvider != nil {
+		provider.cacheMetrics, err = cache.RegisterMetrics(provider.providerConfiguration.CacheMeterProvider, cacheService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register cache metrics: %w", err)
+		}
+	}
 
 	var service IService
 	switch provider.providerConfiguration.Resolver {
@@ -56,12 +85,29 @@ func NewProvider(opts ...ProviderOption) (*Provider, error) {
 				SocketPath:      provider.providerConfiguration.SocketPath,
 				TLSEnabled:      provider.providerConfiguration.Tls,
 				OtelInterceptor: provider.providerConfiguration.OtelIntercept,
+
+				ClientCertificatePath: provider.providerConfiguration.ClientCertPath,
+				ClientKeyPath:         provider.providerConfiguration.ClientKeyPath,
+				HeaderProvider:        provider.providerConfiguration.HeaderProvider,
+				InsecureHeaders:       provider.providerConfiguration.InsecureHeaders,
+
+				RetryBackoff:           provider.providerConfiguration.RetryBackoff,
+				RetryBackoffMax:        provider.providerConfiguration.RetryBackoffMax,
+				RetryBackoffMultiplier: provider.providerConfiguration.RetryBackoffMultiplier,
+				RetryBackoffJitter:     provider.providerConfiguration.RetryBackoffJitter,
+				InfiniteRetries:        provider.providerConfiguration.EventStreamInfiniteRetries,
+				RetryGracePeriod:       provider.providerConfiguration.RetryGracePeriod,
+
+				Prefetch:        provider.providerConfiguration.CachePrefetch,
+				PrefetchContext: prefetchContext(provider.providerConfiguration),
+
+				RequestTimeout: provider.providerConfiguration.RequestTimeout,
 			},
 			cacheService,
 			provider.providerConfiguration.log,
 			provider.providerConfiguration.EventStreamConnectionMaxAttempts)
 	case inProcess:
-		service = process.NewInProcessService(process.Configuration{
+		service = newInProcessService(provider.providerConfiguration, process.Configuration{
 			Host:                    provider.providerConfiguration.Host,
 			Port:                    provider.providerConfiguration.Port,
 			ProviderID:              provider.providerConfiguration.ProviderId,
@@ -74,10 +120,20 @@ func NewProvider(opts ...ProviderOption) (*Provider, error) {
 			CustomSyncProviderUri:   provider.providerConfiguration.CustomSyncProviderUri,
 			GrpcDialOptionsOverride: provider.providerConfiguration.GrpcDialOptionsOverride,
 			RetryGracePeriod:        provider.providerConfiguration.RetryGracePeriod,
+			FatalStatusCodes:        provider.providerConfiguration.FatalStatusCodes,
+			SnapshotPath:            provider.providerConfiguration.SnapshotPath,
+			SnapshotDeadline:        provider.providerConfiguration.SnapshotDeadline,
+			Sources:                 provider.providerConfiguration.FlagSources,
+			ClientCertificatePath:   provider.providerConfiguration.ClientCertPath,
+			ClientKeyPath:           provider.providerConfiguration.ClientKeyPath,
+			HeaderProvider:          provider.providerConfiguration.HeaderProvider,
+			InsecureHeaders:         provider.providerConfiguration.InsecureHeaders,
+			Operators:               provider.providerConfiguration.Operators,
 		})
 	default:
-		service = process.NewInProcessService(process.Configuration{
+		service = newInProcessService(provider.providerConfiguration, process.Configuration{
 			OfflineFlagSource: provider.providerConfiguration.OfflineFlagSourcePath,
+			Operators:         provider.providerConfiguration.Operators,
 		})
 	}
 
@@ -86,51 +142,165 @@ func NewProvider(opts ...ProviderOption) (*Provider, error) {
 	return provider, nil
 }
 
+// prefetchContext is the evaluation context of cache prefetching, the static context merged with the configured
+// context which takes precedence
+func prefetchContext(providerConfiguration *ProviderConfiguration) map[string]interface{} {
+	evalCtx := make(map[string]interface{},
+		len(providerConfiguration.StaticContext)+len(providerConfiguration.CachePrefetchContext))
+	maps.Copy(evalCtx, providerConfiguration.StaticContext)
+	maps.Copy(evalCtx, providerConfiguration.CachePrefetchContext)
+	return evalCtx
+}
+
+// newInProcessService creates the in-process service, which is shared with other providers if configured
+func newInProcessService(providerConfiguration *ProviderConfiguration, cfg process.Configuration) IService {
+	if providerConfiguration.SharedStore != "" {
+		return process.NewSharedInProcessService(providerConfiguration.SharedStore, cfg)
+	}
+	return process.NewInProcessService(cfg)
+}
+
 func (p *Provider) Init(_ of.EvaluationContext) error {
 	p.mtx.Lock()
-	defer p.mtx.Unlock()
-
 	// avoid reinitialization if initialized
 	if p.initialized {
+		p.mtx.Unlock()
 		return nil
 	}
+	if p.initializing {
+		p.mtx.Unlock()
+		return &of.ProviderInitError{ErrorCode: of.ProviderNotReadyCode, Message: "initialization already in progress"}
+	}
 
-	err := p.service.Init()
-	if err != nil {
-		return err
+	results := make(chan initResult)
+	abandoned := make(chan struct{})
+	go p.initialize(results, abandoned)
+
+	if p.providerConfiguration.NonBlockingInit {
+		// initialization completes in the background, the outcome is reported as event
+		close(abandoned)
+		p.initialized = true
+		p.mtx.Unlock()
+		return nil
 	}
 
-	// wait for initialization from the service
-	e := <-p.service.EventChannel()
-	if e.EventType != of.ProviderReady {
-		return fmt.Errorf("provider initialization failed: %s", e.ProviderEventDetails.Message)
+	p.initializing = true
+	p.mtx.Unlock()
+
+	initialized, err := p.awaitInitialization(results, abandoned)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if !p.initializing {
+		// the provider was shut down while waiting
+		return &of.ProviderInitError{ErrorCode: of.ProviderNotReadyCode, Message: "provider shut down during initialization"}
+	}
+	p.initializing = false
+	p.initialized = initialized
+	return err
+}
+
+// awaitInitialization waits for the outcome of the initialization, up to the deadline. It reports whether the provider
+// is initialized, which is also the case if initialization continues in the background after the deadline.
+func (p *Provider) awaitInitialization(results <-chan initResult, abandoned chan<- struct{}) (bool, error) {
+	var deadline <-chan time.Time
+	if p.providerConfiguration.Deadline > 0 {
+		timer := time.NewTimer(p.providerConfiguration.Deadline)
+		defer timer.Stop()
+		deadline = timer.C
 	}
 
-	p.status = of.ReadyState
-	p.initialized = true
+	select {
+	case result := <-results:
+		if result.err != nil {
+			return false, result.err
+		}
+		p.setStatus(statusFromEvent(result.event))
+		return true, nil
+	case <-deadline:
+		// initialization continues in the background, the outcome is reported as event
+		close(abandoned)
+		return true, &of.ProviderInitError{
+			ErrorCode: of.ProviderNotReadyCode,
+			Message:   fmt.Sprintf("provider not ready within the deadline of %s", p.providerConfiguration.Deadline),
+		}
+	}
+}
 
-	// start event handling after the first ready event
-	go func() {
-		for {
-			event := <-p.service.EventChannel()
+// initResult is the outcome of the service initialization
+type initResult struct {
+	event of.Event
+	err   error
+}
+
+// initialize initializes the service and handles its events afterward.
+// The outcome of the initialization is handed to Init through results, unless Init stopped waiting for it by closing
+// abandoned. In that case the outcome is reported as event instead.
+func (p *Provider) initialize(results chan<- initResult, abandoned <-chan struct{}) {
+	event, err := p.initializeService()
+
+	select {
+	case results <- initResult{event: event, err: err}:
+		if err != nil {
+			return
+		}
+		if event.EventType == of.ProviderStale {
+			// the SDK reports a successful initialization as ready, forward the stale state afterward
 			p.eventStream <- event
-			switch event.EventType {
-			case of.ProviderReady:
-			case of.ProviderConfigChange:
-				p.setStatus(of.ReadyState)
-			case of.ProviderError:
-				p.setStatus(of.ErrorState)
+		}
+	case <-abandoned:
+		if err != nil {
+			errorCode := of.GeneralCode
+			var initErr *of.ProviderInitError
+			if errors.As(err, &initErr) {
+				errorCode = initErr.ErrorCode
+			}
+			p.setStatus(statusFromEvent(of.Event{
+				EventType:            of.ProviderError,
+				ProviderEventDetails: of.ProviderEventDetails{ErrorCode: errorCode},
+			}))
+			p.eventStream <- of.Event{
+				ProviderName: "flagd",
+				EventType:    of.ProviderError,
+				ProviderEventDetails: of.ProviderEventDetails{
+					Message:   err.Error(),
+					ErrorCode: errorCode,
+				},
 			}
+			return
 		}
-	}()
+		p.setStatus(statusFromEvent(event))
+		p.eventStream <- event
+	}
+
+	for {
+		event := <-p.service.EventChannel()
+		// update the status before forwarding, so that handlers observe the new status
+		p.setStatus(statusFromEvent(event))
+		p.eventStream <- event
+	}
+}
+
+// initializeService initializes the service and waits for its first event, which must report it as ready or stale
+func (p *Provider) initializeService() (of.Event, error) {
+	err := p.service.Init()
+	if err != nil {
+		return of.Event{}, err
+	}
 
-	return nil
+	// wait for initialization from the service
+	e := <-p.service.EventChannel()
+	switch e.EventType {
+	case of.ProviderReady, of.ProviderStale:
+		// stale if initialized from a snapshot, the service emits a ready event once the sync caught up
+		return e, nil
+	default:
+		return e, fmt.Errorf("provider initialization failed: %s", e.ProviderEventDetails.Message)
+	}
 }
 
 func (p *Provider) Status() of.State {
-	p.mtx.RLock()
-	defer p.mtx.RUnlock()
-	return p.status
+	return p.status.Load().(of.State)
 }
 
 func (p *Provider) Shutdown() {
@@ -138,13 +308,28 @@ func (p *Provider) Shutdown() {
 	defer p.mtx.Unlock()
 
 	p.initialized = false
+	p.initializing = false
 	p.service.Shutdown()
+
+	// the metrics callback references the cache, it would otherwise outlive the provider
+	if p.cacheMetrics != nil {
+		if err := p.cacheMetrics.Unregister(); err != nil {
+			p.providerConfiguration.log.Error(err, "failed to unregister cache metrics")
+		}
+		p.cacheMetrics = nil
+	}
 }
 
 func (p *Provider) EventChannel() <-chan of.Event {
 	return p.eventStream
 }
 
+// CacheStats returns the hit, miss, eviction and purge counts of the flag evaluation cache.
+// Caching is only performed by the rpc resolver, other resolvers report zero values.
+func (p *Provider) CacheStats() CacheStats {
+	return p.cache.Stats()
+}
+
 // Hooks flagd provider does not have any hooks, returns empty slice
 func (p *Provider) Hooks() []of.Hook {
 	return []of.Hook{}
@@ -160,35 +345,139 @@ func (p *Provider) Metadata() of.Metadata {
 func (p *Provider) BooleanEvaluation(
 	ctx context.Context, flagKey string, defaultValue bool, evalCtx of.FlattenedContext,
 ) of.BoolResolutionDetail {
-	return p.service.ResolveBoolean(ctx, flagKey, defaultValue, evalCtx)
+	if p.notReady() {
+		return of.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notReadyResolutionDetail()}
+	}
+	return p.service.ResolveBoolean(ctx, flagKey, defaultValue, p.enrich(ctx, evalCtx))
 }
 
 func (p *Provider) StringEvaluation(
 	ctx context.Context, flagKey string, defaultValue string, evalCtx of.FlattenedContext,
 ) of.StringResolutionDetail {
-	return p.service.ResolveString(ctx, flagKey, defaultValue, evalCtx)
+	if p.notReady() {
+		return of.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notReadyResolutionDetail()}
+	}
+	return p.service.ResolveString(ctx, flagKey, defaultValue, p.enrich(ctx, evalCtx))
 }
 
 func (p *Provider) FloatEvaluation(
 	ctx context.Context, flagKey string, defaultValue float64, evalCtx of.FlattenedContext,
 ) of.FloatResolutionDetail {
-	return p.service.ResolveFloat(ctx, flagKey, defaultValue, evalCtx)
+	if p.notReady() {
+		return of.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notReadyResolutionDetail()}
+	}
+	return p.service.ResolveFloat(ctx, flagKey, defaultValue, p.enrich(ctx, evalCtx))
 }
 
 func (p *Provider) IntEvaluation(
 	ctx context.Context, flagKey string, defaultValue int64, evalCtx of.FlattenedContext,
 ) of.IntResolutionDetail {
-	return p.service.ResolveInt(ctx, flagKey, defaultValue, evalCtx)
+	if p.notReady() {
+		return of.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notReadyResolutionDetail()}
+	}
+	return p.service.ResolveInt(ctx, flagKey, defaultValue, p.enrich(ctx, evalCtx))
 }
 
 func (p *Provider) ObjectEvaluation(
 	ctx context.Context, flagKey string, defaultValue interface{}, evalCtx of.FlattenedContext,
 ) of.InterfaceResolutionDetail {
-	return p.service.ResolveObject(ctx, flagKey, defaultValue, evalCtx)
+	if p.notReady() {
+		return of.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notReadyResolutionDetail()}
+	}
+	return p.service.ResolveObject(ctx, flagKey, defaultValue, p.enrich(ctx, evalCtx))
+}
+
+// flagResolver is implemented by services evaluating a single flag regardless of its type
+type flagResolver interface {
+	Resolve(ctx context.Context, key string, evalCtx map[string]interface{}) of.InterfaceResolutionDetail
+}
+
+// ResolveFlag evaluates a single flag regardless of its type. The value is of type bool, string, float64 or
+// map[string]interface{}. Services without single flag evaluations of any type, i.e. the rpc resolver, evaluate
+// all flags and pick the flag. Disabled flags are reported as not found.
+func (p *Provider) ResolveFlag(ctx context.Context, flagKey string, evalCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	if p.notReady() {
+		return of.InterfaceResolutionDetail{ProviderResolutionDetail: notReadyResolutionDetail()}
+	}
+
+	evalCtx = p.enrich(ctx, evalCtx)
+	if resolver, ok := p.service.(flagResolver); ok {
+		return resolver.Resolve(ctx, flagKey, evalCtx)
+	}
+
+	details, err := p.service.ResolveAll(ctx, evalCtx)
+	if err != nil {
+		return of.InterfaceResolutionDetail{ProviderResolutionDetail: of.ProviderResolutionDetail{
+			ResolutionError: of.NewGeneralResolutionError(err.Error()),
+			Reason:          of.ErrorReason,
+		}}
+	}
+	detail, ok := details[flagKey]
+	if !ok {
+		return of.InterfaceResolutionDetail{ProviderResolutionDetail: of.ProviderResolutionDetail{
+			ResolutionError: of.NewFlagNotFoundResolutionError(fmt.Sprintf("flag: %s not found", flagKey)),
+			Reason:          of.ErrorReason,
+		}}
+	}
+	return detail
+}
+
+// ResolveAll evaluates all flags for the evaluation context at once.
+// The values are of type bool, string, float64 or map[string]interface{}, keyed by the flag key.
+// Disabled flags are not part of the result.
+func (p *Provider) ResolveAll(ctx context.Context, evalCtx of.FlattenedContext) (
+	map[string]of.InterfaceResolutionDetail, error) {
+	if p.notReady() {
+		return nil, of.NewProviderNotReadyResolutionError("provider not ready")
+	}
+	return p.service.ResolveAll(ctx, p.enrich(ctx, evalCtx))
 }
 
 func (p *Provider) setStatus(status of.State) {
-	p.mtx.Lock()
-	defer p.mtx.Unlock()
-	p.status = status
+	p.status.Store(status)
+}
+
+// enrich merges the static context and the attributes of the context enrichers into the evaluation context.
+// The evaluation context takes precedence over enriched attributes.
+func (p *Provider) enrich(ctx context.Context, evalCtx of.FlattenedContext) of.FlattenedContext {
+	staticContext := p.providerConfiguration.StaticContext
+	enrichers := p.providerConfiguration.ContextEnrichers
+	if len(staticContext) == 0 && len(enrichers) == 0 {
+		return evalCtx
+	}
+
+	enriched := make(of.FlattenedContext, len(staticContext)+len(evalCtx))
+	maps.Copy(enriched, staticContext)
+	for _, enricher := range enrichers {
+		maps.Copy(enriched, enricher(ctx))
+	}
+	maps.Copy(enriched, evalCtx)
+	return enriched
+}
+
+// statusFromEvent returns the status the provider transitions to with the event
+func statusFromEvent(event of.Event) of.State {
+	switch event.EventType {
+	case of.ProviderStale:
+		return of.StaleState
+	case of.ProviderError:
+		if event.ErrorCode == of.ProviderFatalCode {
+			return of.FatalState
+		}
+		return of.ErrorState
+	default:
+		return of.ReadyState
+	}
+}
+
+// notReady reports whether the provider has not been initialized yet, e.g. during non-blocking initialization
+func (p *Provider) notReady() bool {
+	return p.Status() == of.NotReadyState
+}
+
+func notReadyResolutionDetail() of.ProviderResolutionDetail {
+	return of.ProviderResolutionDetail{
+		ResolutionError: of.NewProviderNotReadyResolutionError("provider not ready"),
+		Reason:          of.ErrorReason,
+	}
 }
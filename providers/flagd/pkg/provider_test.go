@@ -1,14 +1,21 @@
 package flagd
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/open-feature/flagd/core/pkg/sync"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/mock"
 	process "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/in_process"
 	of "github.com/open-feature/go-sdk/openfeature"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -269,6 +276,30 @@ func TestNewProvider(t *testing.T) {
 				WithOfflineFilePath("offlineFilePath"),
 			},
 		},
+		{
+			name:             "with contextual cache",
+			expectedResolver: rpc,
+			expectHost:       defaultHost,
+			expectPort:       defaultRpcPort,
+			expectCacheType:  cache.ContextualValue,
+			expectCacheSize:  500,
+			expectMaxRetries: defaultMaxEventStreamRetries,
+			options: []ProviderOption{
+				WithContextualCache(500),
+			},
+		},
+		{
+			name:             "with ttl cache without size limit",
+			expectedResolver: rpc,
+			expectHost:       defaultHost,
+			expectPort:       defaultRpcPort,
+			expectCacheType:  cache.TTLValue,
+			expectCacheSize:  0,
+			expectMaxRetries: defaultMaxEventStreamRetries,
+			options: []ProviderOption{
+				WithTTLCache(0, time.Second),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -398,24 +429,18 @@ func TestEventHandling(t *testing.T) {
 		t.Errorf("expected initial status to be not ready, but got %v", provider.Status())
 	}
 
-	// push events to local event channel
-	go func() {
-		// initial ready event
-		customChan <- of.Event{
-			ProviderName: "flagd",
-			EventType:    of.ProviderReady,
-		}
-
-		customChan <- of.Event{
-			ProviderName: "flagd",
-			EventType:    of.ProviderConfigChange,
-		}
+	// push events to local event channel one at a time, so that the status can be checked in between
+	push := func(eventType of.EventType) {
+		go func() {
+			customChan <- of.Event{
+				ProviderName: "flagd",
+				EventType:    eventType,
+			}
+		}()
+	}
 
-		customChan <- of.Event{
-			ProviderName: "flagd",
-			EventType:    of.ProviderError,
-		}
-	}()
+	// initial ready event
+	push(of.ProviderReady)
 
 	// Check initial readiness
 	err = provider.Init(of.EvaluationContext{})
@@ -424,6 +449,7 @@ func TestEventHandling(t *testing.T) {
 	}
 
 	// check event emitting from provider in order
+	push(of.ProviderConfigChange)
 	event := <-provider.EventChannel()
 	if event.EventType != of.ProviderConfigChange {
 		t.Errorf("expected event %v, got %v", of.ProviderReady, event.EventType)
@@ -433,6 +459,17 @@ func TestEventHandling(t *testing.T) {
 		t.Errorf("expected status to be ready, but got %v", provider.Status())
 	}
 
+	push(of.ProviderStale)
+	event = <-provider.EventChannel()
+	if event.EventType != of.ProviderStale {
+		t.Errorf("expected event %v, got %v", of.ProviderStale, event.EventType)
+	}
+
+	if provider.Status() != of.StaleState {
+		t.Errorf("expected status to be stale, but got %v", provider.Status())
+	}
+
+	push(of.ProviderError)
 	event = <-provider.EventChannel()
 	if event.EventType != of.ProviderError {
 		t.Errorf("expected event %v, got %v", of.ProviderError, event.EventType)
@@ -441,6 +478,34 @@ func TestEventHandling(t *testing.T) {
 	if provider.Status() != of.ErrorState {
 		t.Errorf("expected status to be error, but got %v", provider.Status())
 	}
+
+	// recovery of the connection
+	push(of.ProviderReady)
+	event = <-provider.EventChannel()
+	if event.EventType != of.ProviderReady {
+		t.Errorf("expected event %v, got %v", of.ProviderReady, event.EventType)
+	}
+
+	if provider.Status() != of.ReadyState {
+		t.Errorf("expected status to be ready, but got %v", provider.Status())
+	}
+
+	// fatal error, e.g. a fatal status code of the sync stream
+	go func() {
+		customChan <- of.Event{
+			ProviderName:         "flagd",
+			EventType:            of.ProviderError,
+			ProviderEventDetails: of.ProviderEventDetails{ErrorCode: of.ProviderFatalCode},
+		}
+	}()
+	event = <-provider.EventChannel()
+	if event.EventType != of.ProviderError {
+		t.Errorf("expected event %v, got %v", of.ProviderError, event.EventType)
+	}
+
+	if provider.Status() != of.FatalState {
+		t.Errorf("expected status to be fatal, but got %v", provider.Status())
+	}
 }
 
 func TestInitializeOnlyOnce(t *testing.T) {
@@ -486,3 +551,383 @@ func TestInitializeOnlyOnce(t *testing.T) {
 	}
 
 }
+
+func TestInitializeStale(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventChan := make(chan of.Event)
+
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().Init().Times(1)
+	svcMock.EXPECT().EventChannel().Return(eventChan).AnyTimes()
+
+	provider, err := NewProvider()
+	provider.service = svcMock
+
+	if err != nil {
+		t.Fatal("error creating new provider", err)
+	}
+
+	// service initialized from a snapshot
+	go func() {
+		eventChan <- of.Event{
+			ProviderName: "flagd",
+			EventType:    of.ProviderStale,
+		}
+	}()
+
+	err = provider.Init(of.EvaluationContext{})
+	if err != nil {
+		t.Fatal("error initialization provider", err)
+	}
+
+	if provider.Status() != of.StaleState {
+		t.Errorf("expected status to be stale, but got %v", provider.Status())
+	}
+
+	// the stale event is forwarded after initialization
+	event := <-provider.EventChannel()
+	if event.EventType != of.ProviderStale {
+		t.Errorf("expected event %v, got %v", of.ProviderStale, event.EventType)
+	}
+}
+
+func TestInitializeDeadline(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventChan := make(chan of.Event)
+
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().Init().Times(1)
+	svcMock.EXPECT().EventChannel().Return(eventChan).AnyTimes()
+
+	provider, err := NewProvider(WithDeadline(50 * time.Millisecond))
+	provider.service = svcMock
+
+	if err != nil {
+		t.Fatal("error creating new provider", err)
+	}
+
+	// the service does not become ready within the deadline
+	err = provider.Init(of.EvaluationContext{})
+
+	var initErr *of.ProviderInitError
+	if !errors.As(err, &initErr) || initErr.ErrorCode != of.ProviderNotReadyCode {
+		t.Fatalf("expected initialization to fail with %s, got %v", of.ProviderNotReadyCode, err)
+	}
+
+	detail := provider.BooleanEvaluation(context.Background(), "flag", true, of.FlattenedContext{})
+	if !detail.Value || detail.ResolutionDetail().ErrorCode != of.ProviderNotReadyCode {
+		t.Errorf("expected default value with %s error, got %+v", of.ProviderNotReadyCode, detail)
+	}
+
+	// initialization completes after the deadline
+	go func() {
+		eventChan <- of.Event{
+			ProviderName: "flagd",
+			EventType:    of.ProviderReady,
+		}
+	}()
+
+	event := <-provider.EventChannel()
+	if event.EventType != of.ProviderReady {
+		t.Errorf("expected event %v, got %v", of.ProviderReady, event.EventType)
+	}
+
+	if provider.Status() != of.ReadyState {
+		t.Errorf("expected status to be ready, but got %v", provider.Status())
+	}
+}
+
+func TestEvaluationDuringInitialization(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventChan := make(chan of.Event)
+
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().Init().Times(1)
+	svcMock.EXPECT().EventChannel().Return(eventChan).AnyTimes()
+
+	provider, err := NewProvider()
+	provider.service = svcMock
+
+	if err != nil {
+		t.Fatal("error creating new provider", err)
+	}
+
+	initErr := make(chan error)
+	go func() {
+		initErr <- provider.Init(of.EvaluationContext{})
+	}()
+
+	// evaluations do not wait for the blocking initialization
+	evaluated := make(chan of.BoolResolutionDetail)
+	go func() {
+		evaluated <- provider.BooleanEvaluation(context.Background(), "flag", true, of.FlattenedContext{})
+	}()
+
+	select {
+	case detail := <-evaluated:
+		if detail.ResolutionDetail().ErrorCode != of.ProviderNotReadyCode {
+			t.Errorf("expected %s error during initialization, got %+v", of.ProviderNotReadyCode, detail)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("evaluation blocked by the initialization")
+	}
+
+	eventChan <- of.Event{ProviderName: "flagd", EventType: of.ProviderReady}
+	if err := <-initErr; err != nil {
+		t.Fatal("error initialization provider", err)
+	}
+
+	if provider.Status() != of.ReadyState {
+		t.Errorf("expected status to be ready, but got %v", provider.Status())
+	}
+}
+
+func TestInitializeNonBlocking(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventChan := make(chan of.Event)
+
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().Init().Times(1)
+	svcMock.EXPECT().EventChannel().Return(eventChan).AnyTimes()
+	svcMock.EXPECT().ResolveString(gomock.Any(), "flag", "default", gomock.Any()).
+		Return(of.StringResolutionDetail{Value: "value"}).Times(1)
+
+	provider, err := NewProvider(WithNonBlockingInit())
+	provider.service = svcMock
+
+	if err != nil {
+		t.Fatal("error creating new provider", err)
+	}
+
+	// initialization returns before the service is ready
+	err = provider.Init(of.EvaluationContext{})
+	if err != nil {
+		t.Fatal("error initialization provider", err)
+	}
+
+	if provider.Status() != of.NotReadyState {
+		t.Errorf("expected status to be not ready, but got %v", provider.Status())
+	}
+
+	detail := provider.StringEvaluation(context.Background(), "flag", "default", of.FlattenedContext{})
+	if detail.Value != "default" || detail.ResolutionDetail().ErrorCode != of.ProviderNotReadyCode {
+		t.Errorf("expected default value with %s error, got %+v", of.ProviderNotReadyCode, detail)
+	}
+
+	go func() {
+		eventChan <- of.Event{
+			ProviderName: "flagd",
+			EventType:    of.ProviderReady,
+		}
+	}()
+
+	event := <-provider.EventChannel()
+	if event.EventType != of.ProviderReady {
+		t.Errorf("expected event %v, got %v", of.ProviderReady, event.EventType)
+	}
+
+	detail = provider.StringEvaluation(context.Background(), "flag", "default", of.FlattenedContext{})
+	if detail.Value != "value" {
+		t.Errorf("expected flag to be evaluated once ready, got %+v", detail)
+	}
+}
+
+func TestInitializeNonBlockingFailure(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().Init().Return(&of.ProviderInitError{ErrorCode: of.ProviderFatalCode, Message: "denied"}).Times(1)
+
+	provider, err := NewProvider(WithNonBlockingInit())
+	provider.service = svcMock
+
+	if err != nil {
+		t.Fatal("error creating new provider", err)
+	}
+
+	err = provider.Init(of.EvaluationContext{})
+	if err != nil {
+		t.Fatal("error initialization provider", err)
+	}
+
+	// the failure is reported as event
+	event := <-provider.EventChannel()
+	if event.EventType != of.ProviderError || event.ErrorCode != of.ProviderFatalCode {
+		t.Errorf("expected %v event with %v, got %v", of.ProviderError, of.ProviderFatalCode, event)
+	}
+
+	if provider.Status() != of.FatalState {
+		t.Errorf("expected status to be fatal, but got %v", provider.Status())
+	}
+}
+
+func TestEvaluationContextEnrichment(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type tenantKey struct{}
+
+	provider, err := NewProvider(
+		WithStaticContext(map[string]interface{}{"region": "eu-west-1", "version": "1.0.0", "stage": "prod"}),
+		WithContextEnricher(func(ctx context.Context) map[string]interface{} {
+			return map[string]interface{}{"tenant": ctx.Value(tenantKey{}), "version": "1.1.0"}
+		}),
+	)
+	if err != nil {
+		t.Fatal("error creating new provider", err)
+	}
+
+	expected := map[string]interface{}{
+		"region":       "eu-west-1",
+		"version":      "1.1.0",
+		"tenant":       "acme",
+		"targetingKey": "user",
+		"email":        "user@acme.com",
+		"stage":        "canary",
+	}
+
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().ResolveBoolean(gomock.Any(), "flag", false, gomock.Eq(expected)).
+		Return(of.BoolResolutionDetail{Value: true}).Times(1)
+	provider.service = svcMock
+	provider.setStatus(of.ReadyState)
+
+	// when
+
+	// the enricher takes precedence over static context, the evaluation context over both
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	detail := provider.BooleanEvaluation(ctx, "flag", false, of.FlattenedContext{
+		"targetingKey": "user",
+		"email":        "user@acme.com",
+		"stage":        "canary",
+	})
+
+	// then
+	if !detail.Value {
+		t.Errorf("expected flag to be evaluated with the enriched context")
+	}
+}
+
+func TestSharedStore(t *testing.T) {
+	// given
+	offlinePath := filepath.Join(t.TempDir(), "flags.json")
+	err := os.WriteFile(offlinePath, []byte(`{
+		"flags": {
+			"flag": {
+				"state": "ENABLED",
+				"variants": {"on": true, "off": false},
+				"defaultVariant": "off",
+				"targeting": {"if": [{"==": [{"var": "domain"}, "checkout"]}, "on", null]}
+			}
+		}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// providers of two domains share the flag store, enrichment remains specific to each provider
+	providers := map[string]*Provider{}
+	for _, domain := range []string{"checkout", "search"} {
+		provider, err := NewProvider(
+			WithFileResolver(),
+			WithOfflineFilePath(offlinePath),
+			WithSharedStore(t.Name()),
+			WithStaticContext(map[string]interface{}{"domain": domain}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := provider.Init(of.EvaluationContext{}); err != nil {
+			t.Fatal(err)
+		}
+		providers[domain] = provider
+	}
+
+	// when
+	checkout := providers["checkout"].BooleanEvaluation(t.Context(), "flag", false, of.FlattenedContext{})
+	providers["checkout"].Shutdown()
+	search := providers["search"].BooleanEvaluation(t.Context(), "flag", true, of.FlattenedContext{})
+	providers["search"].Shutdown()
+
+	// then
+	if !checkout.Value {
+		t.Error("expected the flag to be enabled for the checkout domain")
+	}
+	if search.Value || search.Error() != nil {
+		t.Errorf("expected the flag to be disabled for the search domain, got error %v", search.Error())
+	}
+}
+
+func TestCacheMetrics(t *testing.T) {
+	reader := metric.NewManualReader()
+
+	provider, err := NewProvider(WithCacheMetrics(metric.NewMeterProvider(metric.WithReader(reader))))
+	if err != nil {
+		t.Fatal("error creating new provider", err)
+	}
+
+	provider.cache.GetCache().Add("flag", true)
+	provider.cache.GetCache().Get("flag")
+	provider.cache.GetCache().Get("other")
+
+	if stats := provider.CacheStats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected one hit and one miss, got %+v", stats)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rm.ScopeMetrics) != 1 {
+		t.Fatalf("expected metrics of one scope, got %d", len(rm.ScopeMetrics))
+	}
+
+	observed := map[string]int64{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		sum, ok := m.Data.(metricdata.Sum[int64])
+		if !ok || len(sum.DataPoints) != 1 {
+			t.Fatalf("unexpected data for metric %s: %v", m.Name, m.Data)
+		}
+		observed[m.Name] = sum.DataPoints[0].Value
+	}
+
+	expected := map[string]int64{
+		"feature_flag.flagd.cache_hit_total":      1,
+		"feature_flag.flagd.cache_miss_total":     1,
+		"feature_flag.flagd.cache_eviction_total": 0,
+		"feature_flag.flagd.cache_purge_total":    0,
+	}
+	if !reflect.DeepEqual(observed, expected) {
+		t.Errorf("unexpected metrics, expected %v, got %v", expected, observed)
+	}
+
+	// shutting down unregisters the metrics callback
+	provider.Shutdown()
+	rm = metricdata.ResourceMetrics{}
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && len(sum.DataPoints) > 0 {
+				t.Errorf("expected no observations after shutdown, got %v for %s", sum.DataPoints, m.Name)
+			}
+		}
+	}
+}
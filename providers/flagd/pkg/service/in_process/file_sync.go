@@ -0,0 +1,178 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	schema "github.com/open-feature/flagd-schemas/json"
+	"github.com/open-feature/flagd/core/pkg/logger"
+	isync "github.com/open-feature/flagd/core/pkg/sync"
+	"github.com/open-feature/flagd/core/pkg/sync/file"
+	"github.com/open-feature/flagd/core/pkg/utils"
+	of "github.com/open-feature/go-sdk/openfeature"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// anyTargetingSchema replaces the targeting schema to accept targeting rules using custom operators
+const anyTargetingSchema = `{"$id": "https://flagd.dev/schema/v0/targeting.json", "type": "object"}`
+
+var (
+	// flagSchema is the compiled flagd flag configuration schema
+	flagSchema = sync.OnceValues(func() (*gojsonschema.Schema, error) {
+		return compileFlagSchema(schema.TargetingSchema)
+	})
+	// customOperatorFlagSchema is the compiled flagd flag configuration schema accepting any targeting rule
+	customOperatorFlagSchema = sync.OnceValues(func() (*gojsonschema.Schema, error) {
+		return compileFlagSchema(anyTargetingSchema)
+	})
+)
+
+func compileFlagSchema(targetingSchema string) (*gojsonschema.Schema, error) {
+	loader := gojsonschema.NewSchemaLoader()
+	if err := loader.AddSchemas(gojsonschema.NewStringLoader(targetingSchema)); err != nil {
+		return nil, fmt.Errorf("failed to load targeting schema: %w", err)
+	}
+	return loader.Compile(gojsonschema.NewStringLoader(schema.FlagSchema))
+}
+
+// fileSync reads flags from a JSON or YAML file, which is watched for changes.
+// Every version of the file is validated against the flagd schema before it is applied. Invalid versions, such as a
+// half-saved file, are rejected with an event carrying the validation errors, so the last valid flag configuration
+// keeps being served.
+type fileSync struct {
+	uri     string
+	watcher *file.Sync
+	schema  func() (*gojsonschema.Schema, error)
+	logger  *logger.Logger
+	events  chan SyncEvent
+}
+
+// assert interface compliance
+var _ EventSync = (*fileSync)(nil)
+
+// newFileSync creates a sync of the file at uri. With custom operators, targeting rules are not validated, as the
+// targeting schema only knows the builtin operators.
+func newFileSync(uri string, customOperators bool, log *logger.Logger) *fileSync {
+	flagsSchema := flagSchema
+	if customOperators {
+		flagsSchema = customOperatorFlagSchema
+	}
+
+	return &fileSync{
+		uri: uri,
+		watcher: &file.Sync{
+			URI:    uri,
+			Logger: log,
+			Mux:    &sync.RWMutex{},
+		},
+		schema: flagsSchema,
+		logger: log,
+		events: make(chan SyncEvent, 10),
+	}
+}
+
+// Init starts watching the file
+func (f *fileSync) Init(ctx context.Context) error {
+	return f.watcher.Init(ctx)
+}
+
+// IsReady returns true once the file is watched
+func (f *fileSync) IsReady() bool {
+	return f.watcher.IsReady()
+}
+
+// Sync sends the flags of the file and of every change of the file
+func (f *fileSync) Sync(ctx context.Context, dataSync chan<- isync.DataSync) error {
+	// the watcher reports changes, the file is read again to validate the raw content
+	changes := make(chan isync.DataSync, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- f.watcher.Sync(ctx, changes)
+	}()
+
+	for {
+		select {
+		case <-changes:
+			f.send(ctx, dataSync)
+		case err := <-done:
+			return err
+		}
+	}
+}
+
+// ReSync sends the current flags of the file
+func (f *fileSync) ReSync(ctx context.Context, dataSync chan<- isync.DataSync) error {
+	f.send(ctx, dataSync)
+	return nil
+}
+
+// Events returns the events of rejected file versions
+func (f *fileSync) Events() chan SyncEvent {
+	return f.events
+}
+
+// send sends the flags of the file if they are valid, an event with the validation errors otherwise
+func (f *fileSync) send(ctx context.Context, dataSync chan<- isync.DataSync) {
+	flags, err := f.read()
+	if err != nil {
+		f.logger.Warn(fmt.Sprintf("rejected flag configuration: %v", err))
+		select {
+		case f.events <- SyncEvent{event: of.ProviderError, invalid: true, message: err.Error()}:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	select {
+	case dataSync <- isync.DataSync{FlagData: flags, Source: f.uri}:
+	case <-ctx.Done():
+	}
+}
+
+// read reads the flag configuration of the file as JSON and validates it against the flagd schema
+func (f *fileSync) read() (string, error) {
+	data, err := os.ReadFile(f.uri)
+	if err != nil {
+		return "", fmt.Errorf("failed to read flag file: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return "", fmt.Errorf("flag file %s is empty", f.uri)
+	}
+
+	flags, err := utils.ConvertToJSON(data, filepath.Ext(f.uri), "")
+	if err != nil {
+		return "", fmt.Errorf("invalid flag file %s: %w", f.uri, err)
+	}
+
+	if err := f.validate(flags); err != nil {
+		return "", fmt.Errorf("invalid flag file %s: %w", f.uri, err)
+	}
+	return flags, nil
+}
+
+// validate validates the flag configuration against the flagd schema
+func (f *fileSync) validate(flags string) error {
+	compiled, err := f.schema()
+	if err != nil {
+		return err
+	}
+
+	result, err := compiled.Validate(gojsonschema.NewStringLoader(flags))
+	if err != nil {
+		return err
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	violations := make([]string, 0, len(result.Errors()))
+	for _, violation := range result.Errors() {
+		violations = append(violations, violation.String())
+	}
+	return errors.New("schema violations: " + strings.Join(violations, "; "))
+}
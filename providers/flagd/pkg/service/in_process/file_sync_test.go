@@ -0,0 +1,148 @@
+package process
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+const validFlags = `{
+	"flags": {
+		"myBoolFlag": {
+			"state": "ENABLED",
+			"variants": {"on": true, "off": false},
+			"defaultVariant": "on"
+		}
+	}
+}`
+
+// awaitEvent waits for an event of the given type with a message containing message, skipping other events.
+// Writing a file may be observed in several steps, e.g. the truncated file before its content, which emit additional
+// events.
+func awaitEvent(t *testing.T, service *InProcess, eventType of.EventType, message string) of.Event {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-service.events:
+			if event.EventType == eventType && strings.Contains(event.Message, message) {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s event", eventType)
+		}
+	}
+}
+
+func TestInProcessOfflineModeRejectsInvalidFlags(t *testing.T) {
+	// given
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, offlinePath, []byte(validFlags))
+
+	service := NewInProcessService(Configuration{OfflineFlagSource: offlinePath})
+	t.Cleanup(service.Shutdown)
+
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, service, of.ProviderReady)
+
+	tests := map[string]struct {
+		flags         string
+		expectMessage string
+	}{
+		"invalid json": {
+			flags:         `{"flags": {"myBoolFlag": {`,
+			expectMessage: "unexpected EOF",
+		},
+		"schema violation": {
+			flags:         strings.Replace(validFlags, "ENABLED", "UNKNOWN", 1),
+			expectMessage: "schema violations",
+		},
+		"empty file": {
+			flags:         "",
+			expectMessage: "is empty",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			writeFile(t, offlinePath, []byte(test.flags))
+
+			// then
+			// the provider is stale rather than failed, as it keeps serving flags
+			event := awaitEvent(t, service, of.ProviderStale, test.expectMessage)
+			if event.ErrorCode != of.ParseErrorCode {
+				t.Errorf("expected error code %s, got %s", of.ParseErrorCode, event.ErrorCode)
+			}
+
+			// the last valid flag configuration keeps being served
+			detail := service.ResolveBoolean(t.Context(), "myBoolFlag", false, map[string]interface{}{})
+			if detail.Error() != nil || !detail.Value {
+				t.Errorf("expected the last valid flag configuration, got %v with error %v", detail.Value, detail.Error())
+			}
+		})
+	}
+
+	// the provider recovers with the next valid flag configuration
+	writeFile(t, offlinePath, []byte(strings.Replace(validFlags, `"defaultVariant": "on"`, `"defaultVariant": "off"`, 1)))
+	awaitEvent(t, service, of.ProviderReady, "")
+
+	detail := service.ResolveBoolean(t.Context(), "myBoolFlag", true, map[string]interface{}{})
+	if detail.Value {
+		t.Error("expected the updated flag configuration")
+	}
+}
+
+func TestInProcessOfflineModeInvalidFlagsFailInitialization(t *testing.T) {
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, offlinePath, []byte(`{"flags": {"myBoolFlag": {"state": "UNKNOWN"}}}`))
+
+	service := NewInProcessService(Configuration{OfflineFlagSource: offlinePath})
+	t.Cleanup(service.Shutdown)
+
+	if err := service.Init(); err == nil {
+		t.Error("expected initialization with invalid flags to fail")
+	}
+}
+
+func TestInProcessOfflineModeYAML(t *testing.T) {
+	// given
+	offlinePath := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, offlinePath, []byte(`
+flags:
+  myStringFlag:
+    state: ENABLED
+    variants:
+      hi: hello
+      bye: goodbye
+    defaultVariant: bye
+    targeting:
+      if:
+        - "==":
+            - var: email
+            - user@example.com
+        - hi
+        - null
+`))
+
+	service := NewInProcessService(Configuration{OfflineFlagSource: offlinePath})
+	t.Cleanup(service.Shutdown)
+
+	// when
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, service, of.ProviderReady)
+
+	// then
+	detail := service.ResolveString(t.Context(), "myStringFlag", "", map[string]interface{}{"email": "user@example.com"})
+	if detail.Value != "hello" {
+		t.Errorf("expected hello, got %s", detail.Value)
+	}
+}
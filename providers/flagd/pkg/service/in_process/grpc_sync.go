@@ -4,14 +4,24 @@ import (
 	"buf.build/gen/go/open-feature/flagd/grpc/go/flagd/sync/v1/syncv1grpc"
 	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/sync/v1"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/open-feature/flagd/core/pkg/logger"
 	"github.com/open-feature/flagd/core/pkg/sync"
 	grpccredential "github.com/open-feature/flagd/core/pkg/sync/grpc/credentials"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/certificate"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"os"
+	"strconv"
 	msync "sync"
 	"time"
 )
@@ -21,6 +31,7 @@ const (
 	defaultKeepaliveTime    = 30 * time.Second
 	defaultKeepaliveTimeout = 5 * time.Second
 
+	// retryPolicy is completed with the retryable status codes, which exclude the configured fatal status codes
 	retryPolicy = `{
 		  "methodConfig": [
 			{
@@ -34,29 +45,44 @@ const (
 				"InitialBackoff": "1s",
 				"MaxBackoff": "5s",
 				"BackoffMultiplier": 2.0,
-				"RetryableStatusCodes": [
-				  "CANCELLED",
-				  "UNKNOWN",
-				  "INVALID_ARGUMENT",
-				  "NOT_FOUND",
-				  "ALREADY_EXISTS",
-				  "PERMISSION_DENIED",
-				  "RESOURCE_EXHAUSTED",
-				  "FAILED_PRECONDITION",
-				  "ABORTED",
-				  "OUT_OF_RANGE",
-				  "UNIMPLEMENTED",
-				  "INTERNAL",
-				  "UNAVAILABLE",
-				  "DATA_LOSS",
-				  "UNAUTHENTICATED"
-				]
+				"RetryableStatusCodes": %s
 			  }
 			}
 		  ]
 		}`
 )
 
+var retryableStatusCodes = []string{
+	"CANCELLED",
+	"UNKNOWN",
+	"INVALID_ARGUMENT",
+	"NOT_FOUND",
+	"ALREADY_EXISTS",
+	"PERMISSION_DENIED",
+	"RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION",
+	"ABORTED",
+	"OUT_OF_RANGE",
+	"UNIMPLEMENTED",
+	"INTERNAL",
+	"UNAVAILABLE",
+	"DATA_LOSS",
+	"UNAUTHENTICATED",
+}
+
+// fatalStatusError is returned by Sync when the sync stream failed with one of the configured fatal status codes
+type fatalStatusError struct {
+	err error
+}
+
+func (e *fatalStatusError) Error() string {
+	return fmt.Sprintf("sync failed with fatal status: %v", e.err)
+}
+
+func (e *fatalStatusError) Unwrap() error {
+	return e.err
+}
+
 // Type aliases for interfaces required by this component - needed for mock generation with gomock
 type FlagSyncServiceClient interface {
 	syncv1grpc.FlagSyncServiceClient
@@ -78,6 +104,18 @@ type Sync struct {
 	Selector                string
 	URI                     string
 	MaxMsgSize              int
+	// FatalStatusCodes are the gRPC status code names (e.g. "UNAUTHENTICATED") on which syncing is not retried
+	FatalStatusCodes []string
+	// ClientCertPath and ClientKeyPath are the client key pair presented for mutual TLS, reloaded on rotation
+	ClientCertPath string
+	ClientKeyPath  string
+	// HeaderProvider returns metadata, e.g. an authorization token, added to every call to the sync service
+	HeaderProvider func(ctx context.Context) map[string]string
+	// InsecureHeaders allows the metadata of the header provider to be sent over insecure connections
+	InsecureHeaders bool
+
+	// sharedConnection is used instead of a connection of its own, it is closed by its owner rather than on shutdown
+	sharedConnection *grpc.ClientConn
 
 	// Runtime state
 	client           FlagSyncServiceClient
@@ -87,6 +125,9 @@ type Sync struct {
 	shutdownComplete chan struct{}
 	shutdownOnce     msync.Once
 	initializer      msync.Once
+	fatalCodes       map[codes.Code]struct{}
+	metadataMu       msync.RWMutex
+	metadata         map[string]interface{}
 }
 
 // Init initializes the gRPC connection and starts background monitoring
@@ -96,11 +137,16 @@ func (g *Sync) Init(ctx context.Context) error {
 	// Initialize channels
 	g.shutdownComplete = make(chan struct{})
 	g.events = make(chan SyncEvent, 10) // Buffered to prevent blocking
-
-	// Establish gRPC connection
-	conn, err := g.createConnection()
-	if err != nil {
-		return fmt.Errorf("failed to create gRPC connection: %w", err)
+	g.fatalCodes = g.parseFatalStatusCodes()
+
+	// Establish gRPC connection, unless it is shared
+	conn := g.sharedConnection
+	if conn == nil {
+		var err error
+		conn, err = g.createConnection()
+		if err != nil {
+			return fmt.Errorf("failed to create gRPC connection: %w", err)
+		}
 	}
 
 	g.connection = conn
@@ -134,12 +180,19 @@ func (g *Sync) buildDialOptions() ([]grpc.DialOption, error) {
 	var dialOptions []grpc.DialOption
 
 	// Transport credentials
-	tCredentials, err := g.CredentialBuilder.Build(g.Secure, g.CertPath)
+	tCredentials, err := g.buildTransportCredentials()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build transport credentials: %w", err)
 	}
 	dialOptions = append(dialOptions, grpc.WithTransportCredentials(tCredentials))
 
+	if g.HeaderProvider != nil {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(headerCredentials{
+			provider: g.HeaderProvider,
+			insecure: g.InsecureHeaders,
+		}))
+	}
+
 	// Call options for message size
 	if g.MaxMsgSize > 0 {
 		callOptions := []grpc.CallOption{grpc.MaxCallRecvMsgSize(g.MaxMsgSize)}
@@ -155,11 +208,121 @@ func (g *Sync) buildDialOptions() ([]grpc.DialOption, error) {
 	}
 	dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepaliveParams))
 
-	dialOptions = append(dialOptions, grpc.WithDefaultServiceConfig(retryPolicy))
+	serviceConfig, err := g.buildRetryPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build retry policy: %w", err)
+	}
+	dialOptions = append(dialOptions, grpc.WithDefaultServiceConfig(serviceConfig))
 
 	return dialOptions, nil
 }
 
+// buildTransportCredentials builds the transport credentials, presenting the client certificate if configured
+func (g *Sync) buildTransportCredentials() (credentials.TransportCredentials, error) {
+	if g.ClientCertPath == "" {
+		return g.CredentialBuilder.Build(g.Secure, g.CertPath)
+	}
+
+	if !g.Secure {
+		return nil, errors.New("provided a client certificate, but requested an insecure connection")
+	}
+
+	clientCertificate, err := certificate.NewClientCertificate(g.ClientCertPath, g.ClientKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		GetClientCertificate: clientCertificate.GetClientCertificate,
+	}
+
+	if g.CertPath != "" {
+		caCert, err := os.ReadFile(g.CertPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read file %s: %w", g.CertPath, err)
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("invalid certificate provided at path: %s", g.CertPath)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// headerCredentials adds the metadata of the provider to every call, the provider is invoked per call so that
+// short-lived tokens are refreshed
+type headerCredentials struct {
+	provider func(ctx context.Context) map[string]string
+	insecure bool
+}
+
+func (h headerCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	return h.provider(ctx), nil
+}
+
+// RequireTransportSecurity requires TLS, unless insecure connections were explicitly allowed, e.g. to a local sidecar
+// terminating TLS
+func (h headerCredentials) RequireTransportSecurity() bool {
+	return !h.insecure
+}
+
+// parseFatalStatusCodes converts the configured fatal status code names, unknown names are ignored
+func (g *Sync) parseFatalStatusCodes() map[codes.Code]struct{} {
+	fatalCodes := make(map[codes.Code]struct{}, len(g.FatalStatusCodes))
+	for _, name := range g.FatalStatusCodes {
+		code, err := parseStatusCode(name)
+		if err != nil {
+			g.Logger.Warn(fmt.Sprintf("ignoring unknown fatal status code %q", name))
+			continue
+		}
+		fatalCodes[code] = struct{}{}
+	}
+	return fatalCodes
+}
+
+// buildRetryPolicy builds the service config, fatal status codes are not retried by the gRPC client
+func (g *Sync) buildRetryPolicy() (string, error) {
+	names := make([]string, 0, len(retryableStatusCodes))
+	for _, name := range retryableStatusCodes {
+		code, err := parseStatusCode(name)
+		if err != nil {
+			return "", err
+		}
+		if _, fatal := g.fatalCodes[code]; !fatal {
+			names = append(names, name)
+		}
+	}
+
+	codesJSON, err := json.Marshal(names)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(retryPolicy, codesJSON), nil
+}
+
+// parseStatusCode parses a gRPC status code name such as "UNAUTHENTICATED"
+func parseStatusCode(name string) (codes.Code, error) {
+	var code codes.Code
+	err := code.UnmarshalJSON([]byte(strconv.Quote(name)))
+	return code, err
+}
+
+// isFatal reports whether err carries one of the configured fatal status codes
+func (g *Sync) isFatal(err error) bool {
+	if len(g.fatalCodes) == 0 {
+		return false
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	_, fatal := g.fatalCodes[st.Code()]
+	return fatal
+}
+
 // ReSync performs a one-time fetch of all flags
 func (g *Sync) ReSync(ctx context.Context, dataSync chan<- sync.DataSync) error {
 	g.Logger.Debug("performing ReSync - fetching all flags")
@@ -213,6 +376,12 @@ func (g *Sync) Sync(ctx context.Context, dataSync chan<- sync.DataSync) error {
 				return ctx.Err()
 			}
 
+			if g.isFatal(err) {
+				g.Logger.Error(fmt.Sprintf("sync cycle failed with fatal status: %v, not retrying", err))
+				g.sendEvent(ctx, SyncEvent{event: of.ProviderError, fatal: true, message: err.Error()})
+				return &fatalStatusError{err: err}
+			}
+
 			g.Logger.Warn(fmt.Sprintf("sync cycle failed: %v, retrying...", err))
 			g.sendEvent(ctx, SyncEvent{event: of.ProviderError})
 
@@ -242,10 +411,34 @@ func (g *Sync) performSyncCycle(ctx context.Context, dataSync chan<- sync.DataSy
 
 	g.Logger.Info("sync stream established, starting to receive flags")
 
+	// fetch the metadata before the flags of the stream are applied, so that evaluations of these flags include it
+	g.fetchMetadata(ctx)
+
 	// Handle the stream with proper context cancellation
 	return g.handleFlagSync(ctx, stream, dataSync)
 }
 
+// fetchMetadata fetches the metadata of the sync service, keeping the previous metadata if the call fails
+func (g *Sync) fetchMetadata(ctx context.Context) {
+	rsp, err := g.client.GetMetadata(ctx, &v1.GetMetadataRequest{})
+	if err != nil {
+		// the sync service may not implement metadata
+		g.Logger.Debug(fmt.Sprintf("failed to fetch sync metadata: %v", err))
+		return
+	}
+
+	g.metadataMu.Lock()
+	defer g.metadataMu.Unlock()
+	g.metadata = rsp.GetMetadata().AsMap()
+}
+
+// Metadata returns the metadata of the sync service
+func (g *Sync) Metadata() map[string]interface{} {
+	g.metadataMu.RLock()
+	defer g.metadataMu.RUnlock()
+	return g.metadata
+}
+
 // handleFlagSync processes messages from the sync stream with proper context handling
 func (g *Sync) handleFlagSync(ctx context.Context, stream syncv1grpc.FlagSyncService_SyncFlagsClient, dataSync chan<- sync.DataSync) error {
 	// Mark as ready on first successful stream
@@ -419,8 +612,8 @@ func (g *Sync) Shutdown() error {
 		close(g.events)
 	}
 
-	// Close gRPC connection
-	if g.connection != nil {
+	// Close gRPC connection, unless it is shared
+	if g.connection != nil && g.connection != g.sharedConnection {
 		if err := g.connection.Close(); err != nil {
 			g.Logger.Error(fmt.Sprintf("error closing gRPC connection: %v", err))
 			return err
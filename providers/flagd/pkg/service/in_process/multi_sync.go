@@ -0,0 +1,144 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	msync "sync"
+
+	"github.com/open-feature/flagd/core/pkg/sync"
+)
+
+// multiSync chains multiple sync providers into a single one.
+// Data of all sources is forwarded as is, the flag store merges it based on the source of each update.
+type multiSync struct {
+	syncs []sync.ISync
+
+	events chan SyncEvent
+	// forwarders tracks the goroutines forwarding events of the sync providers
+	forwarders msync.WaitGroup
+}
+
+func newMultiSync(syncs []sync.ISync) *multiSync {
+	return &multiSync{
+		syncs:  syncs,
+		events: make(chan SyncEvent, 10),
+	}
+}
+
+// Init initializes all sync providers and starts forwarding their events
+func (m *multiSync) Init(ctx context.Context) error {
+	for _, s := range m.syncs {
+		if err := s.Init(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range m.syncs {
+		if eventSync, ok := s.(EventSync); ok {
+			m.forwarders.Add(1)
+			go m.forwardEvents(ctx, eventSync.Events())
+		}
+	}
+	return nil
+}
+
+func (m *multiSync) forwardEvents(ctx context.Context, events chan SyncEvent) {
+	defer m.forwarders.Done()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			select {
+			case m.events <- event:
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// IsReady reports whether all sync providers are ready
+func (m *multiSync) IsReady() bool {
+	for _, s := range m.syncs {
+		if !s.IsReady() {
+			return false
+		}
+	}
+	return true
+}
+
+// Sync runs all sync providers. It returns once all of them completed, or as soon as one of them failed.
+func (m *multiSync) Sync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	errs := make(chan error, len(m.syncs))
+	for _, s := range m.syncs {
+		go func(s sync.ISync) {
+			// buffered, so the remaining providers never block after an early return
+			errs <- s.Sync(ctx, dataSync)
+		}(s)
+	}
+
+	for range m.syncs {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReSync requests the full flag configuration of all sync providers
+func (m *multiSync) ReSync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	var errs []error
+	for _, s := range m.syncs {
+		if err := s.ReSync(ctx, dataSync); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Events returns the merged events of all sync providers supporting events
+func (m *multiSync) Events() chan SyncEvent {
+	return m.events
+}
+
+// Metadata merges the metadata of the sync providers providing it, metadata of later providers takes precedence
+func (m *multiSync) Metadata() map[string]interface{} {
+	metadata := map[string]interface{}{}
+	for _, s := range m.syncs {
+		if metadataSync, ok := s.(MetadataSync); ok {
+			for k, v := range metadataSync.Metadata() {
+				metadata[k] = v
+			}
+		}
+	}
+	return metadata
+}
+
+// Shutdown shuts down the sync providers supporting it.
+// The context passed to Init and Sync must be cancelled beforehand.
+func (m *multiSync) Shutdown() error {
+	m.forwarders.Wait()
+
+	var errs []error
+	for _, s := range m.syncs {
+		if shutdowner, ok := s.(Shutdowner); ok {
+			if err := shutdowner.Shutdown(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to shut down sync providers: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+var (
+	_ EventSync    = &multiSync{}
+	_ MetadataSync = &multiSync{}
+)
@@ -0,0 +1,106 @@
+package process
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/open-feature/flagd/core/pkg/evaluator"
+	"github.com/open-feature/flagd/core/pkg/logger"
+)
+
+// Operator is a custom JSONLogic operator usable in targeting rules. It is called with the evaluated arguments of the
+// operator and the evaluation context of the evaluation, which includes the $flagd properties, and returns the result
+// of the operation.
+type Operator func(args []interface{}, evalCtx map[string]interface{}) interface{}
+
+var (
+	// reservedOperators are operators which are not registered with the JSONLogic library before an evaluator is
+	// created, the operators of flagd, or which are part of JSONLogic but not implemented by the library
+	reservedOperators = map[string]struct{}{
+		evaluator.FractionEvaluationName:       {},
+		evaluator.LegacyFractionEvaluationName: {},
+		evaluator.SemVerEvaluationName:         {},
+		evaluator.StartsWithEvaluationName:     {},
+		evaluator.EndsWithEvaluationName:       {},
+		"log":                                  {},
+		"method":                               {},
+	}
+
+	operatorsMu sync.Mutex
+	// registeredOperators maps the names of the registered custom operators to their functions. JSONLogic operators
+	// are registered process-wide and never removed, so a name can only be used by a single function.
+	registeredOperators = map[string]uintptr{}
+)
+
+// ValidateOperators verifies that custom operators do not replace builtin operators, nor custom operators of the
+// same name registered by other providers
+func ValidateOperators(operators map[string]Operator) error {
+	operatorsMu.Lock()
+	defer operatorsMu.Unlock()
+
+	for name, operator := range operators {
+		if err := validateOperator(name, operator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOperator validates a custom operator, operatorsMu must be held
+func validateOperator(name string, operator Operator) error {
+	if name == "" || operator == nil {
+		return fmt.Errorf("custom operator %q requires a name and a function", name)
+	}
+	if registered, ok := registeredOperators[name]; ok {
+		if registered != operator.pointer() {
+			return fmt.Errorf("custom operator %q is already registered with another function", name)
+		}
+		return nil
+	}
+	if _, ok := reservedOperators[name]; ok || isJSONLogicOperator(name) {
+		return fmt.Errorf("custom operator %q replaces a builtin operator", name)
+	}
+	return nil
+}
+
+// isJSONLogicOperator reports whether the JSONLogic library knows the operator, a rule of an unknown operator is
+// invalid
+func isJSONLogicOperator(name string) bool {
+	return jsonlogic.ValidateJsonLogic(map[string]interface{}{name: []interface{}{}})
+}
+
+// operatorOptions registers the custom operators with the evaluator. Operators conflicting with builtin operators or
+// with custom operators registered by other services are not registered.
+func operatorOptions(operators map[string]Operator, log *logger.Logger) []evaluator.JSONEvaluatorOption {
+	operatorsMu.Lock()
+	defer operatorsMu.Unlock()
+
+	options := make([]evaluator.JSONEvaluatorOption, 0, len(operators))
+	for name, operator := range operators {
+		if err := validateOperator(name, operator); err != nil {
+			log.Error(fmt.Sprintf("ignoring custom operator: %v", err))
+			continue
+		}
+		registeredOperators[name] = operator.pointer()
+		options = append(options, evaluator.WithEvaluator(name, operator.jsonLogic))
+	}
+	return options
+}
+
+// pointer identifies the function of the operator. Closures of the same function literal share their code, so they
+// can not be told apart.
+func (o Operator) pointer() uintptr {
+	return reflect.ValueOf(o).Pointer()
+}
+
+// jsonLogic adapts the operator to the JSONLogic operator signature, which passes a single argument unwrapped
+func (o Operator) jsonLogic(values interface{}, data interface{}) interface{} {
+	args, ok := values.([]interface{})
+	if !ok {
+		args = []interface{}{values}
+	}
+	evalCtx, _ := data.(map[string]interface{})
+	return o(args, evalCtx)
+}
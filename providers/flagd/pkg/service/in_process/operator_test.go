@@ -0,0 +1,124 @@
+package process
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-feature/flagd/core/pkg/logger"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+// cidr matches an IP address against a CIDR block
+func cidr(args []interface{}, _ map[string]interface{}) interface{} {
+	if len(args) != 2 {
+		return false
+	}
+	ip, _ := args[0].(string)
+	block, _ := args[1].(string)
+
+	_, network, err := net.ParseCIDR(block)
+	if err != nil {
+		return false
+	}
+	return network.Contains(net.ParseIP(ip))
+}
+
+// isTargetingKey compares the single argument to the targeting key of the evaluation context
+func isTargetingKey(args []interface{}, evalCtx map[string]interface{}) interface{} {
+	return len(args) == 1 && args[0] == evalCtx[of.TargetingKey]
+}
+
+func TestInProcessOfflineModeCustomOperators(t *testing.T) {
+	// given
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+
+	err := os.WriteFile(offlinePath, []byte(`{
+		"flags": {
+			"internalFlag": {
+				"state": "ENABLED",
+				"variants": {"on": true, "off": false},
+				"defaultVariant": "off",
+				"targeting": {"if": [{"cidr": [{"var": "ip"}, "10.0.0.0/8"]}, "on", null]}
+			},
+			"adminFlag": {
+				"state": "ENABLED",
+				"variants": {"on": true, "off": false},
+				"defaultVariant": "off",
+				"targeting": {"if": [{"is_targeting_key": "admin"}, "on", null]}
+			}
+		}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewInProcessService(Configuration{
+		OfflineFlagSource: offlinePath,
+		Operators: map[string]Operator{
+			"cidr":             cidr,
+			"is_targeting_key": isTargetingKey,
+		},
+	})
+	t.Cleanup(service.Shutdown)
+
+	// when
+	err = service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, service, of.ProviderReady)
+
+	// then
+	tests := map[string]struct {
+		flag    string
+		evalCtx map[string]interface{}
+		expect  bool
+	}{
+		"cidr match":            {flag: "internalFlag", evalCtx: map[string]interface{}{"ip": "10.1.2.3"}, expect: true},
+		"cidr mismatch":         {flag: "internalFlag", evalCtx: map[string]interface{}{"ip": "192.168.1.1"}, expect: false},
+		"context match":         {flag: "adminFlag", evalCtx: map[string]interface{}{of.TargetingKey: "admin"}, expect: true},
+		"context mismatch":      {flag: "adminFlag", evalCtx: map[string]interface{}{of.TargetingKey: "user"}, expect: false},
+		"missing context value": {flag: "internalFlag", evalCtx: map[string]interface{}{}, expect: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			detail := service.ResolveBoolean(t.Context(), test.flag, false, test.evalCtx)
+			if detail.Error() != nil {
+				t.Fatal(detail.Error())
+			}
+			if detail.Value != test.expect {
+				t.Errorf("expected %v, got %v", test.expect, detail.Value)
+			}
+		})
+	}
+}
+
+func TestValidateOperatorsRejectsBuiltin(t *testing.T) {
+	for _, name := range []string{"fractional", "merge", "log", "missing_some"} {
+		err := ValidateOperators(map[string]Operator{name: cidr})
+		if err == nil {
+			t.Errorf("expected the builtin operator %q to be rejected", name)
+		}
+	}
+}
+
+func TestValidateOperatorsRejectsConflicts(t *testing.T) {
+	log := logger.NewLogger(NewRaw(), false)
+	options := operatorOptions(map[string]Operator{"conflictingOperator": cidr}, log)
+	if len(options) != 1 {
+		t.Fatalf("expected the operator to be registered, got %d options", len(options))
+	}
+
+	if err := ValidateOperators(map[string]Operator{"conflictingOperator": cidr}); err != nil {
+		t.Errorf("expected the same operator to be accepted again, got %v", err)
+	}
+	if err := ValidateOperators(map[string]Operator{"conflictingOperator": isTargetingKey}); err == nil {
+		t.Error("expected an operator of the same name with another function to be rejected")
+	}
+	if options := operatorOptions(map[string]Operator{"conflictingOperator": isTargetingKey}, log); len(options) != 0 {
+		t.Error("expected the conflicting operator not to be registered")
+	}
+}
@@ -2,9 +2,12 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -15,9 +18,9 @@ import (
 	"github.com/open-feature/flagd/core/pkg/model"
 	"github.com/open-feature/flagd/core/pkg/store"
 	isync "github.com/open-feature/flagd/core/pkg/sync"
-	"github.com/open-feature/flagd/core/pkg/sync/file"
 	"github.com/open-feature/flagd/core/pkg/sync/grpc"
 	"github.com/open-feature/flagd/core/pkg/sync/grpc/credentials"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/stale"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"golang.org/x/exp/maps"
 )
@@ -37,6 +40,7 @@ type InProcess struct {
 	// Core components
 	evaluator       evaluator.IEvaluator
 	syncProvider    isync.ISync
+	sources         []string // URIs of the flag sources in order of increasing priority
 	logger          *logger.Logger
 	configuration   Configuration
 	serviceMetadata model.Metadata
@@ -53,9 +57,28 @@ type InProcess struct {
 	shutdownOnce     sync.Once
 
 	// Stateless coordination using sync.Once
-	initOnce            sync.Once
-	sendReadyOnNextData sync.Once
-	staleTimer          *staleTimer
+	initOnce   sync.Once
+	staleTimer *stale.Timer
+	// readySent is set once the ready event was sent, and cleared on errors so it is sent again on recovery
+	readySent atomic.Bool
+	// fatal is set once the sync failed with a fatal status code, further sync events are ignored
+	fatal bool
+
+	// stateMu orders snapshot loading against live sync data, so a snapshot never overrides newer flags
+	stateMu      sync.Mutex
+	liveReceived bool
+	// received tracks the sources which delivered data, the service is ready once all sources did
+	received map[string]struct{}
+	// snapshot holds the last applied flag configuration per source
+	snapshot map[string]string
+
+	// contextMu guards the sync context, which is merged into the evaluation context of every evaluation
+	contextMu    sync.RWMutex
+	syncContexts map[string]map[string]interface{}
+	syncContext  map[string]interface{}
+
+	// syncMetadata is the metadata of the sync provider as of the last sync data, it is replaced rather than modified
+	syncMetadata atomic.Pointer[map[string]interface{}]
 }
 
 // shutdownChannels groups all shutdown-related channels
@@ -66,38 +89,6 @@ type shutdownChannels struct {
 	initError        chan error
 }
 
-// staleTimer manages the stale connection timer with thread safety
-type staleTimer struct {
-	timer *time.Timer
-	mu    sync.Mutex
-}
-
-// newStaleTimer creates a new thread-safe stale timer
-func newStaleTimer() *staleTimer {
-	return &staleTimer{}
-}
-
-// start starts or restarts the stale timer
-func (st *staleTimer) start(duration time.Duration, callback func()) {
-	st.mu.Lock()
-	defer st.mu.Unlock()
-
-	if st.timer == nil {
-		st.timer = time.AfterFunc(duration, callback)
-	}
-}
-
-// stop stops the stale timer
-func (st *staleTimer) stop() {
-	st.mu.Lock()
-	defer st.mu.Unlock()
-
-	if st.timer != nil {
-		st.timer.Stop()
-		st.timer = nil
-	}
-}
-
 // Configuration holds all configuration for the InProcess service
 type Configuration struct {
 	Host                    any
@@ -112,6 +103,48 @@ type Configuration struct {
 	GrpcDialOptionsOverride []googlegrpc.DialOption
 	CertificatePath         string
 	RetryGracePeriod        int
+	FatalStatusCodes        []string
+	// ClientCertificatePath and ClientKeyPath are the client key pair presented for mutual TLS
+	ClientCertificatePath string
+	ClientKeyPath         string
+	// HeaderProvider returns metadata added to every call to the gRPC sync service
+	HeaderProvider func(ctx context.Context) map[string]string
+	// InsecureHeaders allows the metadata of the header provider to be sent over insecure connections
+	InsecureHeaders bool
+	// SnapshotPath is the file the last successfully applied flag configuration is persisted to
+	SnapshotPath string
+	// SnapshotDeadline is the time to wait for the sync before initializing from the snapshot
+	SnapshotDeadline time.Duration
+	// Sources chains multiple flag sources, taking precedence over the single source configuration above
+	Sources []FlagSource
+	// Operators are custom JSONLogic operators available to targeting rules, by name
+	Operators map[string]Operator
+
+	// connection is a gRPC connection shared with other services, see SharedService
+	connection *googlegrpc.ClientConn
+}
+
+// SourceType is the type of flag source
+type SourceType string
+
+const (
+	// SourceFile reads flags from a local file, which is watched for changes
+	SourceFile SourceType = "file"
+	// SourceGrpc syncs flags from a gRPC sync service, using the connection configuration of the service
+	SourceGrpc SourceType = "grpc"
+	// SourceCustom obtains flags from a custom sync provider
+	SourceCustom SourceType = "custom"
+)
+
+// FlagSource configures one of multiple chained flag sources
+type FlagSource struct {
+	Type SourceType
+	// Path of the flag configuration file of a file source
+	Path string
+	// SyncProvider of a custom source
+	SyncProvider isync.ISync
+	// Uri identifies the custom source, data sent by the sync provider must use it as source
+	Uri string
 }
 
 // EventSync interface for sync providers that support events
@@ -123,6 +156,11 @@ type EventSync interface {
 // SyncEvent represents an event from the sync provider
 type SyncEvent struct {
 	event of.EventType
+	// fatal marks an error event after which the sync provider stopped retrying
+	fatal bool
+	// invalid marks an error event of rejected flag data, the last valid flag configuration keeps being served
+	invalid bool
+	message string
 }
 
 // Shutdowner interface for graceful shutdown
@@ -130,23 +168,33 @@ type Shutdowner interface {
 	Shutdown() error
 }
 
+// MetadataSync is a sync provider providing metadata of its flag source, which is added to the flag metadata of
+// every evaluation
+type MetadataSync interface {
+	isync.ISync
+	Metadata() map[string]interface{}
+}
+
 // NewInProcessService creates a new InProcess service with the given configuration
 func NewInProcessService(cfg Configuration) *InProcess {
 	log := logger.NewLogger(NewRaw(), false)
-	syncProvider, uri := createSyncProvider(cfg, log)
+	syncProvider, sources := createSyncProvider(cfg, log)
 
 	flagStore := store.NewFlags()
-	flagStore.FlagSources = append(flagStore.FlagSources, uri)
+	flagStore.FlagSources = append(flagStore.FlagSources, sources...)
 
 	return &InProcess{
-		evaluator:           evaluator.NewJSON(log, flagStore),
-		syncProvider:        syncProvider,
-		logger:              log,
-		configuration:       cfg,
-		serviceMetadata:     createServiceMetadata(cfg),
-		events:              make(chan of.Event, eventChannelBuffer),
-		staleTimer:          newStaleTimer(),
-		sendReadyOnNextData: sync.Once{}, // Armed and ready to fire on first data
+		evaluator:       evaluator.NewJSON(log, flagStore, operatorOptions(cfg.Operators, log)...),
+		syncProvider:    syncProvider,
+		sources:         sources,
+		logger:          log,
+		configuration:   cfg,
+		serviceMetadata: createServiceMetadata(cfg),
+		events:          make(chan of.Event, eventChannelBuffer),
+		staleTimer:      &stale.Timer{},
+		received:        map[string]struct{}{},
+		snapshot:        map[string]string{},
+		syncContexts:    map[string]map[string]interface{}{},
 	}
 }
 
@@ -224,16 +272,54 @@ func (i *InProcess) runEventSyncMonitor() {
 
 // handleSyncEvent processes individual sync events
 func (i *InProcess) handleSyncEvent(event SyncEvent) {
+	if i.fatal {
+		return
+	}
+
 	switch event.event {
 	case of.ProviderError:
+		if event.invalid {
+			i.handleInvalidData(event.message)
+			return
+		}
+		if event.fatal {
+			i.handleProviderFatal(event.message)
+			return
+		}
 		i.handleProviderError()
-		// Reset the sync.Once so it can fire again on recovery
-		i.sendReadyOnNextData = sync.Once{}
+		// Re-arm the ready event so it is sent again on recovery
+		i.readySent.Store(false)
 	case of.ProviderReady:
 		i.handleProviderReady()
 	}
 }
 
+// handleInvalidData handles rejected flag data. Invalid data fails initialization, afterward the last valid flag
+// configuration keeps being served, hence the provider is reported stale rather than failed until valid data is
+// received, which emits a ready event again.
+func (i *InProcess) handleInvalidData(message string) {
+	select {
+	case <-i.shutdownChannels.initSuccess:
+	default:
+		select {
+		case i.shutdownChannels.initError <- errors.New(message):
+		default:
+		}
+		return
+	}
+
+	i.events <- of.Event{
+		ProviderName: providerName,
+		EventType:    of.ProviderStale,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message:   "rejected invalid flag configuration: " + message,
+			ErrorCode: of.ParseErrorCode,
+		},
+	}
+	// Re-arm the ready event so it is sent again on recovery
+	i.readySent.Store(false)
+}
+
 // handleProviderError handles provider error events by starting stale timer
 func (i *InProcess) handleProviderError() {
 	i.events <- of.Event{
@@ -243,7 +329,7 @@ func (i *InProcess) handleProviderError() {
 	}
 
 	// Start stale timer - when it expires, send error event
-	i.staleTimer.start(time.Duration(i.configuration.RetryGracePeriod)*time.Second, func() {
+	i.staleTimer.Start(time.Duration(i.configuration.RetryGracePeriod)*time.Second, func() {
 		i.events <- of.Event{
 			ProviderName:         providerName,
 			EventType:            of.ProviderError,
@@ -252,9 +338,24 @@ func (i *InProcess) handleProviderError() {
 	})
 }
 
+// handleProviderFatal emits a fatal error event, the provider does not recover from it
+func (i *InProcess) handleProviderFatal(message string) {
+	i.fatal = true
+	i.staleTimer.Stop()
+
+	i.events <- of.Event{
+		ProviderName: providerName,
+		EventType:    of.ProviderError,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message:   message,
+			ErrorCode: of.ProviderFatalCode,
+		},
+	}
+}
+
 // handleProviderReady handles provider ready events by stopping stale timer
 func (i *InProcess) handleProviderReady() {
-	i.staleTimer.stop()
+	i.staleTimer.Stop()
 }
 
 // startDataSyncProcess starts the main data synchronization goroutine
@@ -312,7 +413,26 @@ func (i *InProcess) runDataSyncListener() {
 
 // processSyncData handles individual sync data updates
 func (i *InProcess) processSyncData(data isync.DataSync) {
-	changes, _, err := i.evaluator.SetState(data)
+	i.stateMu.Lock()
+	changes, resync, err := i.evaluator.SetState(data)
+	var snapshot map[string]string
+	if err == nil {
+		i.liveReceived = true
+		if slices.Contains(i.sources, data.Source) {
+			i.received[data.Source] = struct{}{}
+		}
+		snapshot = i.updateSnapshot(data)
+		i.setSyncContext(data)
+		i.updateSyncMetadata()
+	}
+	// a custom single source may use any source name, chained sources must report their URI
+	synced := len(i.sources) <= 1 || len(i.received) >= len(i.sources)
+	i.stateMu.Unlock()
+
+	// the snapshot is written outside the lock, so evaluations do not wait on disk I/O. Sync data is processed
+	// sequentially by this listener, hence snapshots are still written in order.
+	i.persistSnapshot(snapshot)
+
 	if err != nil {
 		i.events <- of.Event{
 			ProviderName:         providerName,
@@ -322,14 +442,25 @@ func (i *InProcess) processSyncData(data isync.DataSync) {
 		return
 	}
 
+	if resync && len(i.sources) > 1 {
+		// a flag deleted from a source may still be defined by a source of lower priority
+		i.wg.Add(1)
+		go i.resyncSources(i.ctx)
+	}
+
+	if !synced {
+		// wait for the remaining sources before reporting readiness
+		return
+	}
+
 	i.logger.Info("staletimer stop")
 	// Stop stale timer - we've successfully received and processed data
-	i.staleTimer.stop()
+	i.staleTimer.Stop()
 
-	// Send ready event using sync.Once - handles initial ready and recovery automatically
-	i.sendReadyOnNextData.Do(func() {
+	// Send ready event once - handles initial ready and recovery automatically
+	if i.readySent.CompareAndSwap(false, true) {
 		i.events <- of.Event{ProviderName: providerName, EventType: of.ProviderReady}
-	})
+	}
 
 	// Handle initialization completion (only happens once ever)
 	i.initOnce.Do(func() {
@@ -358,15 +489,112 @@ func (i *InProcess) shutdownSyncProvider() {
 	}
 }
 
+// resyncSources requests the full flag configuration of all sources, it stops once ctx is cancelled on shutdown
+func (i *InProcess) resyncSources(ctx context.Context) {
+	defer i.wg.Done()
+	if err := i.syncProvider.ReSync(ctx, i.shutdownChannels.syncData); err != nil && ctx.Err() == nil {
+		i.logger.Warn(fmt.Sprintf("failed to resync flag sources: %v", err))
+	}
+}
+
+// updateSnapshot records the applied flag configuration and returns a copy of the flag configurations to persist, or
+// nil if no snapshot is configured. Must be called with stateMu held.
+func (i *InProcess) updateSnapshot(data isync.DataSync) map[string]string {
+	if i.configuration.SnapshotPath == "" {
+		return nil
+	}
+	i.snapshot[data.Source] = data.FlagData
+	return maps.Clone(i.snapshot)
+}
+
+// persistSnapshot writes the flag configurations to the snapshot file, unless there is nothing to persist
+func (i *InProcess) persistSnapshot(snapshot map[string]string) {
+	if snapshot == nil {
+		return
+	}
+	if err := writeSnapshot(i.configuration.SnapshotPath, snapshot); err != nil {
+		i.logger.Warn(fmt.Sprintf("failed to persist flag configuration snapshot: %v", err))
+	}
+}
+
+// loadSnapshot applies the persisted flag configuration, unless live sync data has been received in the meantime.
+// It reports whether the snapshot was applied.
+func (i *InProcess) loadSnapshot() bool {
+	snapshot, err := readSnapshot(i.configuration.SnapshotPath)
+	if err != nil {
+		i.logger.Warn(fmt.Sprintf("unable to initialize from snapshot, waiting for sync: %v", err))
+		return false
+	}
+
+	i.stateMu.Lock()
+	defer i.stateMu.Unlock()
+
+	if i.liveReceived {
+		return false
+	}
+
+	applied := false
+	for _, source := range i.sources {
+		flagData, ok := snapshot[source]
+		if !ok {
+			continue
+		}
+		if _, _, err := i.evaluator.SetState(isync.DataSync{FlagData: flagData, Source: source}); err != nil {
+			i.logger.Warn(fmt.Sprintf("unable to initialize source %s from snapshot: %v", source, err))
+			continue
+		}
+		applied = true
+	}
+	if !applied {
+		i.logger.Warn("unable to initialize from snapshot, waiting for sync: no flag configuration of the configured sources")
+	}
+	return applied
+}
+
+// snapshotDeadline returns a channel firing once the sync missed the deadline for initializing from the snapshot
+func (i *InProcess) snapshotDeadline() <-chan time.Time {
+	if i.configuration.SnapshotPath == "" {
+		return nil
+	}
+	return time.After(i.configuration.SnapshotDeadline)
+}
+
 // waitForInitialization waits for the service to initialize or fail
 func (i *InProcess) waitForInitialization() error {
-	select {
-	case <-i.shutdownChannels.initSuccess:
-		i.logger.Info("InProcess service initialized successfully")
-		return nil
-	case err := <-i.shutdownChannels.initError:
-		return fmt.Errorf("initialization failed: %w", err)
+	deadline := i.snapshotDeadline()
+	for {
+		select {
+		case <-i.shutdownChannels.initSuccess:
+			i.logger.Info("InProcess service initialized successfully")
+			return nil
+		case <-deadline:
+			// only try once, afterward keep waiting for the sync
+			deadline = nil
+			if i.loadSnapshot() {
+				i.logger.Warn("sync not ready within the deadline, initialized from snapshot")
+				i.events <- of.Event{
+					ProviderName:         providerName,
+					EventType:            of.ProviderStale,
+					ProviderEventDetails: of.ProviderEventDetails{Message: "serving flags from snapshot"},
+				}
+				return nil
+			}
+		case err := <-i.shutdownChannels.initError:
+			return i.initializationError(err)
+		}
+	}
+}
+
+// initializationError maps errors of the sync provider during initialization
+func (i *InProcess) initializationError(err error) error {
+	var fatalErr *fatalStatusError
+	if errors.As(err, &fatalErr) {
+		return &of.ProviderInitError{
+			ErrorCode: of.ProviderFatalCode,
+			Message:   fmt.Sprintf("initialization failed: %v", err),
+		}
 	}
+	return fmt.Errorf("initialization failed: %w", err)
 }
 
 // Shutdown gracefully shuts down the service
@@ -375,7 +603,7 @@ func (i *InProcess) Shutdown() {
 		i.logger.Info("starting InProcess service shutdown")
 
 		// Stop stale timer
-		i.staleTimer.stop()
+		i.staleTimer.Stop()
 
 		// Cancel context to signal all goroutines
 		if i.cancelFunc != nil {
@@ -398,17 +626,79 @@ func (i *InProcess) EventChannel() <-chan of.Event {
 	return i.events
 }
 
-// appendMetadata adds service metadata to evaluation metadata
-func (i *InProcess) appendMetadata(evalMetadata model.Metadata) {
+// setSyncContext stores the sync context of the data and merges the sync contexts of all sources.
+// Sync contexts of sources with higher priority take precedence.
+func (i *InProcess) setSyncContext(data isync.DataSync) {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
+
+	if data.SyncContext == nil {
+		delete(i.syncContexts, data.Source)
+	} else {
+		i.syncContexts[data.Source] = data.SyncContext.AsMap()
+	}
+
+	merged := map[string]interface{}{}
+	if len(i.sources) <= 1 {
+		// a custom single source may use any source name
+		for _, syncContext := range i.syncContexts {
+			maps.Copy(merged, syncContext)
+		}
+	} else {
+		for _, source := range i.sources {
+			maps.Copy(merged, i.syncContexts[source])
+		}
+	}
+	i.syncContext = merged
+}
+
+// withSyncContext merges the sync context into the evaluation context, which takes precedence
+func (i *InProcess) withSyncContext(evalCtx map[string]interface{}) map[string]interface{} {
+	i.contextMu.RLock()
+	defer i.contextMu.RUnlock()
+
+	if len(i.syncContext) == 0 {
+		return evalCtx
+	}
+
+	merged := make(map[string]interface{}, len(i.syncContext)+len(evalCtx))
+	maps.Copy(merged, i.syncContext)
+	maps.Copy(merged, evalCtx)
+	return merged
+}
+
+// updateSyncMetadata takes a snapshot of the metadata of the sync provider, which is fetched before the sync data
+// is delivered, so evaluations do not query the sync provider
+func (i *InProcess) updateSyncMetadata() {
+	if metadataSync, ok := i.syncProvider.(MetadataSync); ok {
+		metadata := metadataSync.Metadata()
+		i.syncMetadata.Store(&metadata)
+	}
+}
+
+// appendMetadata adds sync metadata and service metadata to evaluation metadata.
+// Metadata of the flag takes precedence over sync metadata, service metadata takes precedence over both.
+func (i *InProcess) appendMetadata(evalMetadata model.Metadata) model.Metadata {
+	if evalMetadata == nil {
+		evalMetadata = model.Metadata{}
+	}
+	if syncMetadata := i.syncMetadata.Load(); syncMetadata != nil {
+		for k, v := range *syncMetadata {
+			if _, ok := evalMetadata[k]; !ok {
+				evalMetadata[k] = v
+			}
+		}
+	}
 	for k, v := range i.serviceMetadata {
 		evalMetadata[k] = v
 	}
+	return evalMetadata
 }
 
 // ResolveBoolean resolves a boolean flag value
 func (i *InProcess) ResolveBoolean(ctx context.Context, key string, defaultValue bool, evalCtx map[string]interface{}) of.BoolResolutionDetail {
-	value, variant, reason, metadata, err := i.evaluator.ResolveBooleanValue(ctx, "", key, evalCtx)
-	i.appendMetadata(metadata)
+	value, variant, reason, metadata, err := i.evaluator.ResolveBooleanValue(ctx, "", key, i.withSyncContext(evalCtx))
+	metadata = i.appendMetadata(metadata)
 
 	if err != nil {
 		return of.BoolResolutionDetail{
@@ -434,8 +724,8 @@ func (i *InProcess) ResolveBoolean(ctx context.Context, key string, defaultValue
 
 // ResolveString resolves a string flag value
 func (i *InProcess) ResolveString(ctx context.Context, key string, defaultValue string, evalCtx map[string]interface{}) of.StringResolutionDetail {
-	value, variant, reason, metadata, err := i.evaluator.ResolveStringValue(ctx, "", key, evalCtx)
-	i.appendMetadata(metadata)
+	value, variant, reason, metadata, err := i.evaluator.ResolveStringValue(ctx, "", key, i.withSyncContext(evalCtx))
+	metadata = i.appendMetadata(metadata)
 
 	if err != nil {
 		return of.StringResolutionDetail{
@@ -461,8 +751,8 @@ func (i *InProcess) ResolveString(ctx context.Context, key string, defaultValue
 
 // ResolveFloat resolves a float flag value
 func (i *InProcess) ResolveFloat(ctx context.Context, key string, defaultValue float64, evalCtx map[string]interface{}) of.FloatResolutionDetail {
-	value, variant, reason, metadata, err := i.evaluator.ResolveFloatValue(ctx, "", key, evalCtx)
-	i.appendMetadata(metadata)
+	value, variant, reason, metadata, err := i.evaluator.ResolveFloatValue(ctx, "", key, i.withSyncContext(evalCtx))
+	metadata = i.appendMetadata(metadata)
 
 	if err != nil {
 		return of.FloatResolutionDetail{
@@ -488,8 +778,8 @@ func (i *InProcess) ResolveFloat(ctx context.Context, key string, defaultValue f
 
 // ResolveInt resolves an integer flag value
 func (i *InProcess) ResolveInt(ctx context.Context, key string, defaultValue int64, evalCtx map[string]interface{}) of.IntResolutionDetail {
-	value, variant, reason, metadata, err := i.evaluator.ResolveIntValue(ctx, "", key, evalCtx)
-	i.appendMetadata(metadata)
+	value, variant, reason, metadata, err := i.evaluator.ResolveIntValue(ctx, "", key, i.withSyncContext(evalCtx))
+	metadata = i.appendMetadata(metadata)
 
 	if err != nil {
 		return of.IntResolutionDetail{
@@ -515,8 +805,8 @@ func (i *InProcess) ResolveInt(ctx context.Context, key string, defaultValue int
 
 // ResolveObject resolves an object flag value
 func (i *InProcess) ResolveObject(ctx context.Context, key string, defaultValue interface{}, evalCtx map[string]interface{}) of.InterfaceResolutionDetail {
-	value, variant, reason, metadata, err := i.evaluator.ResolveObjectValue(ctx, "", key, evalCtx)
-	i.appendMetadata(metadata)
+	value, variant, reason, metadata, err := i.evaluator.ResolveObjectValue(ctx, "", key, i.withSyncContext(evalCtx))
+	metadata = i.appendMetadata(metadata)
 
 	if err != nil {
 		return of.InterfaceResolutionDetail{
@@ -540,36 +830,129 @@ func (i *InProcess) ResolveObject(ctx context.Context, key string, defaultValue
 	}
 }
 
-// createSyncProvider creates the appropriate sync provider based on configuration
-func createSyncProvider(cfg Configuration, log *logger.Logger) (isync.ISync, string) {
+// Resolve evaluates a single flag regardless of its type. The value is of type bool, string, float64 or
+// map[string]interface{}.
+func (i *InProcess) Resolve(ctx context.Context, key string, evalCtx map[string]interface{}) of.InterfaceResolutionDetail {
+	value := i.evaluator.ResolveAsAnyValue(ctx, "", key, i.withSyncContext(evalCtx))
+	detail := of.InterfaceResolutionDetail{
+		Value: value.Value,
+		ProviderResolutionDetail: of.ProviderResolutionDetail{
+			Reason:       of.Reason(value.Reason),
+			Variant:      value.Variant,
+			FlagMetadata: i.appendMetadata(value.Metadata),
+		},
+	}
+	if value.Error != nil {
+		detail.ResolutionError = mapError(key, value.Error)
+	}
+	return detail
+}
+
+// ResolveAll evaluates all enabled flags for the evaluation context
+func (i *InProcess) ResolveAll(ctx context.Context, evalCtx map[string]interface{}) (
+	map[string]of.InterfaceResolutionDetail, error) {
+	values, _, err := i.evaluator.ResolveAllValues(ctx, "", i.withSyncContext(evalCtx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate flags: %w", err)
+	}
+
+	details := make(map[string]of.InterfaceResolutionDetail, len(values))
+	for _, value := range values {
+		metadata := i.appendMetadata(value.Metadata)
+
+		detail := of.InterfaceResolutionDetail{
+			Value: value.Value,
+			ProviderResolutionDetail: of.ProviderResolutionDetail{
+				Reason:       of.Reason(value.Reason),
+				Variant:      value.Variant,
+				FlagMetadata: metadata,
+			},
+		}
+		if value.Error != nil {
+			detail.ResolutionError = mapError(value.FlagKey, value.Error)
+		}
+		details[value.FlagKey] = detail
+	}
+
+	return details, nil
+}
+
+// createSyncProvider creates the appropriate sync provider based on configuration.
+// It returns the URIs of the flag sources in order of increasing priority.
+func createSyncProvider(cfg Configuration, log *logger.Logger) (isync.ISync, []string) {
+	if len(cfg.Sources) > 0 {
+		return createChainedSyncProvider(cfg, log)
+	}
+
 	if cfg.CustomSyncProvider != nil {
 		log.Info("using custom sync provider at " + cfg.CustomSyncProviderUri)
-		return cfg.CustomSyncProvider, cfg.CustomSyncProviderUri
+		return cfg.CustomSyncProvider, []string{cfg.CustomSyncProviderUri}
 	}
 
 	if cfg.OfflineFlagSource != "" {
 		log.Info("using file sync provider with source: " + cfg.OfflineFlagSource)
-		return &file.Sync{
-			URI:    cfg.OfflineFlagSource,
-			Logger: log,
-			Mux:    &sync.RWMutex{},
-		}, cfg.OfflineFlagSource
+		return createFileSyncProvider(cfg.OfflineFlagSource, cfg, log), []string{cfg.OfflineFlagSource}
 	}
 
 	// Default to gRPC sync provider
 	uri := buildGrpcUri(cfg)
 	log.Info("using gRPC sync provider with URI: " + uri)
 
+	return createGrpcSyncProvider(cfg, uri, log), []string{uri}
+}
+
+// createChainedSyncProvider creates a sync provider combining all configured sources
+func createChainedSyncProvider(cfg Configuration, log *logger.Logger) (isync.ISync, []string) {
+	syncs := make([]isync.ISync, 0, len(cfg.Sources))
+	uris := make([]string, 0, len(cfg.Sources))
+
+	for _, source := range cfg.Sources {
+		switch source.Type {
+		case SourceFile:
+			log.Info("using file sync provider with source: " + source.Path)
+			syncs = append(syncs, createFileSyncProvider(source.Path, cfg, log))
+			uris = append(uris, source.Path)
+		case SourceCustom:
+			log.Info("using custom sync provider at " + source.Uri)
+			syncs = append(syncs, source.SyncProvider)
+			uris = append(uris, source.Uri)
+		case SourceGrpc:
+			uri := buildGrpcUri(cfg)
+			log.Info("using gRPC sync provider with URI: " + uri)
+			syncs = append(syncs, createGrpcSyncProvider(cfg, uri, log))
+			uris = append(uris, uri)
+		default:
+			log.Warn(fmt.Sprintf("ignoring flag source of unknown type %q", source.Type))
+		}
+	}
+
+	if len(syncs) == 1 {
+		return syncs[0], uris
+	}
+	return newMultiSync(syncs), uris
+}
+
+func createFileSyncProvider(path string, cfg Configuration, log *logger.Logger) isync.ISync {
+	return newFileSync(path, len(cfg.Operators) > 0, log)
+}
+
+func createGrpcSyncProvider(cfg Configuration, uri string, log *logger.Logger) *Sync {
 	return &Sync{
 		CredentialBuilder:       &credentials.CredentialBuilder{},
 		GrpcDialOptionsOverride: cfg.GrpcDialOptionsOverride,
 		Logger:                  log,
 		Secure:                  cfg.TLSEnabled,
 		CertPath:                cfg.CertificatePath,
+		ClientCertPath:          cfg.ClientCertificatePath,
+		ClientKeyPath:           cfg.ClientKeyPath,
+		HeaderProvider:          cfg.HeaderProvider,
+		InsecureHeaders:         cfg.InsecureHeaders,
 		ProviderID:              cfg.ProviderID,
 		Selector:                cfg.Selector,
 		URI:                     uri,
-	}, uri
+		FatalStatusCodes:        cfg.FatalStatusCodes,
+		sharedConnection:        cfg.connection,
+	}
 }
 
 // buildGrpcUri constructs the gRPC URI from configuration
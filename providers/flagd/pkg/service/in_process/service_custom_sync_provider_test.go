@@ -2,6 +2,8 @@ package process
 
 import (
 	"testing"
+
+	isync "github.com/open-feature/flagd/core/pkg/sync"
 )
 
 func TestInProcessWithCustomSyncProvider(t *testing.T) {
@@ -13,3 +15,42 @@ func TestInProcessWithCustomSyncProvider(t *testing.T) {
 		t.Fatalf("Expected service.sync to be the mockCustomSyncProvider, but got %s", service.syncProvider)
 	}
 }
+
+// metadataSyncProvider is a custom sync provider with metadata, counting how often its metadata is queried
+type metadataSyncProvider struct {
+	DoNothingCustomSyncProvider
+	metadata map[string]interface{}
+	calls    int
+}
+
+func (m *metadataSyncProvider) Metadata() map[string]interface{} {
+	m.calls++
+	return m.metadata
+}
+
+func TestInProcessSyncMetadataSnapshot(t *testing.T) {
+	syncProvider := &metadataSyncProvider{metadata: map[string]interface{}{"region": "eu"}}
+	service := NewInProcessService(Configuration{CustomSyncProvider: syncProvider, CustomSyncProviderUri: "custom"})
+	// the events of the sync data fit into the buffer of the event channel
+	service.setupShutdownInfrastructure()
+
+	service.processSyncData(isync.DataSync{FlagData: flagRsp, Source: "custom"})
+	syncProvider.metadata = map[string]interface{}{"region": "us"}
+
+	// evaluations use the metadata as of the last sync data, without querying the sync provider
+	for range 3 {
+		detail := service.ResolveBoolean(t.Context(), "myBoolFlag", false, map[string]interface{}{})
+		if detail.FlagMetadata["region"] != "eu" {
+			t.Errorf("expected the sync metadata of the last sync data, got %v", detail.FlagMetadata["region"])
+		}
+	}
+	if syncProvider.calls != 1 {
+		t.Errorf("expected the metadata to be queried once per sync data, got %d queries", syncProvider.calls)
+	}
+
+	service.processSyncData(isync.DataSync{FlagData: flagRsp, Source: "custom"})
+	detail := service.ResolveBoolean(t.Context(), "myBoolFlag", false, map[string]interface{}{})
+	if detail.FlagMetadata["region"] != "us" {
+		t.Errorf("expected the sync metadata to be updated with the sync data, got %v", detail.FlagMetadata["region"])
+	}
+}
@@ -51,3 +51,59 @@ func TestInProcessOfflineMode(t *testing.T) {
 		t.Fatal("Expected scope to be present, but got none")
 	}
 }
+
+func TestInProcessOfflineModeResolveAll(t *testing.T) {
+	// given
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+
+	err := os.WriteFile(offlinePath, []byte(`{
+		"flags": {
+			"myBoolFlag": {
+				"state": "ENABLED",
+				"variants": {"on": true, "off": false},
+				"defaultVariant": "on"
+			},
+			"myStringFlag": {
+				"state": "ENABLED",
+				"variants": {"hi": "hello", "bye": "goodbye"},
+				"defaultVariant": "bye",
+				"targeting": {"if": [{"==": [{"var": "email"}, "user@example.com"]}, "hi", null]}
+			},
+			"myDisabledFlag": {
+				"state": "DISABLED",
+				"variants": {"on": true, "off": false},
+				"defaultVariant": "on"
+			}
+		}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewInProcessService(Configuration{OfflineFlagSource: offlinePath})
+	t.Cleanup(service.Shutdown)
+
+	err = service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// when
+	details, err := service.ResolveAll(context.Background(), map[string]interface{}{"email": "user@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	if len(details) != 2 {
+		t.Fatalf("expected 2 evaluated flags without the disabled flag, got %d", len(details))
+	}
+
+	if detail := details["myBoolFlag"]; detail.Value != true || detail.Reason != of.StaticReason || detail.Variant != "on" {
+		t.Errorf("unexpected evaluation of myBoolFlag: %+v", detail)
+	}
+
+	if detail := details["myStringFlag"]; detail.Value != "hello" || detail.Reason != of.TargetingMatchReason || detail.Variant != "hi" {
+		t.Errorf("unexpected evaluation of myStringFlag: %+v", detail)
+	}
+}
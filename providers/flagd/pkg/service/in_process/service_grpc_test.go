@@ -4,9 +4,14 @@ import (
 	"buf.build/gen/go/open-feature/flagd/grpc/go/flagd/sync/v1/syncv1grpc"
 	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/sync/v1"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/open-feature/go-sdk/openfeature"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 	"log"
 	"net"
 	"testing"
@@ -138,7 +143,7 @@ func TestInProcessProviderEvaluationEnvoy(t *testing.T) {
 	}
 
 	inProcessService := NewInProcessService(Configuration{
-		TargetUri: "envoy://localhost:9211/foo.service",
+		TargetUri:  "envoy://localhost:9211/foo.service",
 		Selector:   scope,
 		TLSEnabled: false,
 	})
@@ -201,16 +206,201 @@ func TestInProcessProviderEvaluationEnvoy(t *testing.T) {
 	}
 }
 
+func TestInProcessProviderFatalStatusCode(t *testing.T) {
+	// given
+	listen, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bufServ := &bufferedServer{
+		listener:       listen,
+		syncFlagsError: status.Error(codes.Unauthenticated, "invalid token"),
+	}
+
+	inProcessService := NewInProcessService(Configuration{
+		Host:             "localhost",
+		Port:             listen.Addr().(*net.TCPAddr).Port,
+		TLSEnabled:       false,
+		FatalStatusCodes: []string{"UNAUTHENTICATED"},
+	})
+	t.Cleanup(inProcessService.Shutdown)
+
+	// when
+
+	// start grpc sync server
+	go func() {
+		serve(bufServ)
+	}()
+
+	// Initialize service
+	err = inProcessService.Init()
+
+	// then
+
+	// initialization must fail with a fatal error instead of retrying
+	var initErr *openfeature.ProviderInitError
+	if !errors.As(err, &initErr) || initErr.ErrorCode != openfeature.ProviderFatalCode {
+		t.Fatalf("expected fatal provider init error, got %v", err)
+	}
+
+	select {
+	case event := <-inProcessService.events:
+		if event.EventType != openfeature.ProviderError || event.ErrorCode != openfeature.ProviderFatalCode {
+			t.Fatalf("expected fatal error event, got %s with error code %s", event.EventType, event.ErrorCode)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Provider did not emit a fatal error event within an acceptable timeframe")
+	}
+}
+
+func TestInProcessProviderHeaderProvider(t *testing.T) {
+	// given
+	listen, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bufServ := &bufferedServer{
+		listener: listen,
+		mockResponses: []*v1.SyncFlagsResponse{
+			{
+				FlagConfiguration: flagRsp,
+			},
+		},
+		syncFlagsMetadata: make(chan metadata.MD, 10),
+	}
+
+	inProcessService := NewInProcessService(Configuration{
+		Host:       "localhost",
+		Port:       listen.Addr().(*net.TCPAddr).Port,
+		TLSEnabled: false,
+		HeaderProvider: func(_ context.Context) map[string]string {
+			return map[string]string{"Authorization": "Bearer token"}
+		},
+		InsecureHeaders: true,
+	})
+	t.Cleanup(inProcessService.Shutdown)
+
+	// when
+
+	// start grpc sync server
+	go func() {
+		serve(bufServ)
+	}()
+
+	err = inProcessService.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	select {
+	case md := <-bufServ.syncFlagsMetadata:
+		if values := md.Get("authorization"); len(values) != 1 || values[0] != "Bearer token" {
+			t.Fatalf("expected the sync stream to be authorized, got metadata %v", md)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("sync stream was not established within an acceptable timeframe")
+	}
+}
+
+func TestInProcessProviderSyncMetadataAndContext(t *testing.T) {
+	// given
+	listen, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syncContext, err := structpb.NewStruct(map[string]interface{}{"region": "eu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncMetadata, err := structpb.NewStruct(map[string]interface{}{"environment": "prod", "configVersion": "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bufServ := &bufferedServer{
+		listener: listen,
+		mockResponses: []*v1.SyncFlagsResponse{
+			{
+				FlagConfiguration: `{
+					"flags": {
+						"regionalFlag": {
+							"state": "ENABLED",
+							"variants": {"on": true, "off": false},
+							"defaultVariant": "off",
+							"targeting": {"if": [{"==": [{"var": "region"}, "eu"]}, "on", null]}
+						}
+					}
+				}`,
+				SyncContext: syncContext,
+			},
+		},
+		metadata: syncMetadata,
+	}
+
+	inProcessService := NewInProcessService(Configuration{
+		Host:       "localhost",
+		Port:       listen.Addr().(*net.TCPAddr).Port,
+		TLSEnabled: false,
+	})
+	t.Cleanup(inProcessService.Shutdown)
+
+	// when
+
+	// start grpc sync server
+	go func() {
+		serve(bufServ)
+	}()
+
+	err = inProcessService.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+
+	// the sync context is merged into the evaluation context
+	detail := inProcessService.ResolveBoolean(context.Background(), "regionalFlag", false, map[string]interface{}{})
+	if !detail.Value || detail.Reason != openfeature.TargetingMatchReason {
+		t.Fatalf("expected flag to be targeted by the sync context, got %+v", detail)
+	}
+
+	// the evaluation context takes precedence over the sync context
+	detail = inProcessService.ResolveBoolean(context.Background(), "regionalFlag", true, map[string]interface{}{"region": "us"})
+	if detail.Value {
+		t.Fatalf("expected the evaluation context to override the sync context, got %+v", detail)
+	}
+
+	// the sync metadata is part of the flag metadata
+	if detail.FlagMetadata["environment"] != "prod" || detail.FlagMetadata["configVersion"] != "42" {
+		t.Fatalf("expected sync metadata in flag metadata, got %v", detail.FlagMetadata)
+	}
+}
 
 // bufferedServer - a mock grpc service backed by buffered connection
 type bufferedServer struct {
 	listener              net.Listener
 	mockResponses         []*v1.SyncFlagsResponse
+	syncFlagsError        error
+	syncFlagsMetadata     chan metadata.MD
+	metadata              *structpb.Struct
 	fetchAllFlagsResponse *v1.FetchAllFlagsResponse
 	fetchAllFlagsError    error
 }
 
 func (b *bufferedServer) SyncFlags(_ *v1.SyncFlagsRequest, stream syncv1grpc.FlagSyncService_SyncFlagsServer) error {
+	if b.syncFlagsMetadata != nil {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		b.syncFlagsMetadata <- md
+	}
+
+	if b.syncFlagsError != nil {
+		return b.syncFlagsError
+	}
+
 	for _, response := range b.mockResponses {
 		err := stream.Send(response)
 		if err != nil {
@@ -230,7 +420,7 @@ func (b *bufferedServer) FetchAllFlags(_ context.Context, _ *v1.FetchAllFlagsReq
 }
 
 func (b *bufferedServer) GetMetadata(_ context.Context, _ *v1.GetMetadataRequest) (*v1.GetMetadataResponse, error) {
-	return &v1.GetMetadataResponse{}, nil
+	return &v1.GetMetadataResponse{Metadata: b.metadata}, nil
 }
 
 // serve serves a bufferedServer. This is a blocking call
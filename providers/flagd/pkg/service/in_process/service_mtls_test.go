@@ -0,0 +1,185 @@
+package process
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/sync/v1"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+// testCA issues certificates for mutual TLS tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns a PEM encoded key pair signed by the CA
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInProcessProviderMutualTLS(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	ca := newTestCA(t)
+
+	caPath := filepath.Join(dir, "ca.crt")
+	writeFile(t, caPath, ca.pem)
+
+	clientCert, clientKey := ca.issue(t, 2, x509.ExtKeyUsageClientAuth)
+	clientCertPath := filepath.Join(dir, "client.crt")
+	clientKeyPath := filepath.Join(dir, "client.key")
+	writeFile(t, clientCertPath, clientCert)
+	writeFile(t, clientKeyPath, clientKey)
+
+	serverCert, serverKey := ca.issue(t, 3, x509.ExtKeyUsageServerAuth)
+	serverKeyPair, err := tls.X509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	listen, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the server rejects clients without a certificate issued by the CA
+	bufServ := &bufferedServer{
+		listener: tls.NewListener(listen, &tls.Config{
+			Certificates: []tls.Certificate{serverKeyPair},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+			NextProtos:   []string{"h2"},
+		}),
+		mockResponses: []*v1.SyncFlagsResponse{
+			{
+				FlagConfiguration: flagRsp,
+			},
+		},
+	}
+
+	inProcessService := NewInProcessService(Configuration{
+		Host:                  "localhost",
+		Port:                  listen.Addr().(*net.TCPAddr).Port,
+		TLSEnabled:            true,
+		CertificatePath:       caPath,
+		ClientCertificatePath: clientCertPath,
+		ClientKeyPath:         clientKeyPath,
+	})
+	t.Cleanup(inProcessService.Shutdown)
+
+	// when
+
+	// start grpc sync server
+	go func() {
+		serve(bufServ)
+	}()
+
+	err = inProcessService.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	expectEvent(t, inProcessService, of.ProviderReady)
+
+	detail := inProcessService.ResolveBoolean(t.Context(), "myBoolFlag", false, map[string]interface{}{})
+	if !detail.Value {
+		t.Fatal("expected flag to be synced over mutual TLS")
+	}
+}
+
+func TestInProcessProviderClientCertificateRequiresTLS(t *testing.T) {
+	grpcSync := &Sync{
+		ClientCertPath: "client.crt",
+		ClientKeyPath:  "client.key",
+		Secure:         false,
+	}
+
+	_, err := grpcSync.buildTransportCredentials()
+	if err == nil {
+		t.Error("expected a client certificate to be rejected for an insecure connection")
+	}
+}
@@ -0,0 +1,108 @@
+package process
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-feature/flagd/core/pkg/logger"
+	isync "github.com/open-feature/flagd/core/pkg/sync"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestInProcessChainedSources(t *testing.T) {
+	base := newChannelSyncProvider("base")
+	overrides := newChannelSyncProvider("overrides")
+
+	service := NewInProcessService(Configuration{
+		Sources: []FlagSource{
+			{Type: SourceCustom, SyncProvider: base, Uri: "base"},
+			{Type: SourceCustom, SyncProvider: overrides, Uri: "overrides"},
+		},
+	})
+	t.Cleanup(service.Shutdown)
+
+	// initialization completes once all sources delivered their flags
+	base.data <- flagRsp
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		overrides.data <- strings.Replace(flagRsp, `"defaultVariant": "on"`, `"defaultVariant": "off"`, 1)
+	}()
+
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, service, of.ProviderReady)
+
+	// later sources take precedence
+	detail := service.ResolveBoolean(context.Background(), "myBoolFlag", true, map[string]interface{}{})
+	if detail.Value {
+		t.Fatal("expected flag to be evaluated from the overrides source")
+	}
+
+	// removing the override falls back to the base source
+	overrides.data <- `{"flags": {}}`
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		detail = service.ResolveBoolean(context.Background(), "myBoolFlag", false, map[string]interface{}{})
+		if detail.Value {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected flag to be evaluated from the base source after removing the override")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestInProcessChainedSourcesIgnoreUnknownSources(t *testing.T) {
+	service := NewInProcessService(Configuration{
+		Sources: []FlagSource{
+			{Type: SourceCustom, SyncProvider: newChannelSyncProvider("base"), Uri: "base"},
+			{Type: SourceCustom, SyncProvider: newChannelSyncProvider("misnamed"), Uri: "overrides"},
+		},
+	})
+	service.setupShutdownInfrastructure()
+
+	isReady := func() bool {
+		select {
+		case <-service.shutdownChannels.initSuccess:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// data of a source which is not configured does not count towards readiness
+	service.processSyncData(isync.DataSync{FlagData: flagRsp, Source: "base"})
+	service.processSyncData(isync.DataSync{FlagData: flagRsp, Source: "misnamed"})
+	if isReady() {
+		t.Fatal("expected the service not to be ready before all configured sources delivered their flags")
+	}
+
+	service.processSyncData(isync.DataSync{FlagData: flagRsp, Source: "overrides"})
+	if !isReady() {
+		t.Fatal("expected the service to be ready once all configured sources delivered their flags")
+	}
+}
+
+func TestCreateChainedSyncProvider(t *testing.T) {
+	syncProvider, sources := createSyncProvider(Configuration{
+		Host: "localhost",
+		Port: 8015,
+		Sources: []FlagSource{
+			{Type: SourceFile, Path: "/flags/overrides.json"},
+			{Type: SourceGrpc},
+		},
+	}, logger.NewLogger(NewRaw(), false))
+
+	if _, ok := syncProvider.(*multiSync); !ok {
+		t.Fatalf("expected chained sync provider, got %T", syncProvider)
+	}
+
+	expected := []string{"/flags/overrides.json", "localhost:8015"}
+	if strings.Join(sources, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected sources %v, got %v", expected, sources)
+	}
+}
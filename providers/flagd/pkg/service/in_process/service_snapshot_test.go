@@ -0,0 +1,152 @@
+package process
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/flagd/core/pkg/sync"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+// channelSyncProvider forwards the flag configurations sent to its channel, simulating a sync provider which is
+// ready whenever the test decides
+type channelSyncProvider struct {
+	data   chan string
+	source string
+
+	mu   gosync.Mutex
+	last string
+}
+
+func newChannelSyncProvider(source string) *channelSyncProvider {
+	return &channelSyncProvider{data: make(chan string, 1), source: source}
+}
+
+func (c *channelSyncProvider) Init(_ context.Context) error {
+	return nil
+}
+
+func (c *channelSyncProvider) IsReady() bool {
+	return true
+}
+
+func (c *channelSyncProvider) Sync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	for {
+		select {
+		case flagData := <-c.data:
+			c.mu.Lock()
+			c.last = flagData
+			c.mu.Unlock()
+			dataSync <- sync.DataSync{FlagData: flagData, Source: c.source}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *channelSyncProvider) ReSync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	c.mu.Lock()
+	last := c.last
+	c.mu.Unlock()
+	if last == "" {
+		return nil
+	}
+
+	select {
+	case dataSync <- sync.DataSync{FlagData: last, Source: c.source}:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func newSnapshotTestService(t *testing.T, snapshotPath string) (*InProcess, chan string) {
+	t.Helper()
+	syncProvider := newChannelSyncProvider("test")
+	service := NewInProcessService(Configuration{
+		CustomSyncProvider:    syncProvider,
+		CustomSyncProviderUri: "test",
+		SnapshotPath:          snapshotPath,
+		SnapshotDeadline:      50 * time.Millisecond,
+	})
+	t.Cleanup(service.Shutdown)
+	return service, syncProvider.data
+}
+
+func expectEvent(t *testing.T, service *InProcess, eventType of.EventType) {
+	t.Helper()
+	select {
+	case event := <-service.events:
+		if event.EventType != eventType {
+			t.Fatalf("expected %s event, got %s", eventType, event.EventType)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for %s event", eventType)
+	}
+}
+
+func TestInProcessWritesSnapshot(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "flags.json")
+	service, data := newSnapshotTestService(t, snapshotPath)
+
+	data <- flagRsp
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, service, of.ProviderReady)
+
+	snapshot, err := readSnapshot(snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot["test"] != flagRsp {
+		t.Errorf("expected snapshot to contain the applied flag configuration, got %v", snapshot)
+	}
+}
+
+func TestInProcessInitializesFromSnapshot(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "flags.json")
+	if err := writeSnapshot(snapshotPath, map[string]string{"test": flagRsp}); err != nil {
+		t.Fatal(err)
+	}
+	service, data := newSnapshotTestService(t, snapshotPath)
+
+	// the sync does not deliver data, initialization falls back to the snapshot after the deadline
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, service, of.ProviderStale)
+
+	detail := service.ResolveBoolean(context.Background(), "myBoolFlag", false, map[string]interface{}{})
+	if !detail.Value {
+		t.Fatal("expected flag to be evaluated from the snapshot")
+	}
+
+	// the live sync catches up
+	data <- strings.Replace(flagRsp, `"defaultVariant": "on"`, `"defaultVariant": "off"`, 1)
+	expectEvent(t, service, of.ProviderReady)
+	expectEvent(t, service, of.ProviderConfigChange)
+
+	detail = service.ResolveBoolean(context.Background(), "myBoolFlag", true, map[string]interface{}{})
+	if detail.Value {
+		t.Fatal("expected flag to be evaluated from the live sync")
+	}
+}
+
+func TestInProcessWithoutSnapshotWaitsForSync(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "missing.json")
+	service, data := newSnapshotTestService(t, snapshotPath)
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		data <- flagRsp
+	}()
+
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, service, of.ProviderReady)
+}
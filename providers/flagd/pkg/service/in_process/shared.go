@@ -0,0 +1,405 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/open-feature/flagd/core/pkg/logger"
+	of "github.com/open-feature/go-sdk/openfeature"
+	googlegrpc "google.golang.org/grpc"
+)
+
+var (
+	sharedMu sync.Mutex
+	// sharedStores holds the stores shared by name, while they are referenced
+	sharedStores = map[string]*sharedStore{}
+)
+
+// sharedStore is the group of in-process services shared under a name, one per selector. Despite its name, it holds no
+// flag store of its own: a sync stream obtains the flags of a single selector and the flag store of flagd keys flags
+// by flag key only, so the flags of several selectors can not be held in one store. Every selector has its own
+// service with its own stream and flag store, only the gRPC connection is shared across selectors. The store is closed
+// once its last service is released.
+type sharedStore struct {
+	name string
+	// source identifies the flag sources and the connection of the store, handles must connect to the same sources
+	source string
+	// headerProvider identifies the header provider of the connection, function values can not be compared
+	headerProvider uintptr
+	// connection is the gRPC connection shared by the services, nil if they do not sync from gRPC
+	connection *googlegrpc.ClientConn
+	// services maps the selectors to their services, guarded by sharedMu
+	services map[string]*sharedService
+}
+
+// sharedService is an in-process service shared by several handles. It is initialized by the first handle and shut
+// down once the last handle is shut down, its events are forwarded to all handles.
+type sharedService struct {
+	store    *sharedStore
+	selector string
+	// settings describes the configuration of the service, handles of the selector must configure it the same way
+	settings string
+	service  *InProcess
+	// refs counts the initialized handles, guarded by sharedMu
+	refs     int
+	initOnce sync.Once
+	initErr  error
+	stopped  chan struct{}
+
+	mu sync.Mutex
+	// handles maps the subscribed handles to the channel closed once they are shut down
+	handles map[*SharedService]chan struct{}
+	// state is the last event reporting the state of the service, it is replayed to handles joining later
+	state *of.Event
+}
+
+// newSharedStore creates the store shared under name, connecting to the flag sources of cfg
+func newSharedStore(name string, cfg Configuration) (*sharedStore, error) {
+	store := &sharedStore{
+		name:           name,
+		source:         sourceIdentity(cfg),
+		headerProvider: funcPointer(cfg.HeaderProvider),
+		services:       map[string]*sharedService{},
+	}
+	if usesGrpcSource(cfg) {
+		log := logger.NewLogger(NewRaw(), false)
+		connection, err := createGrpcSyncProvider(cfg, buildGrpcUri(cfg), log).createConnection()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gRPC connection of shared in-process service %q: %w", name, err)
+		}
+		store.connection = connection
+	}
+	return store, nil
+}
+
+// acquireSharedService returns the service of the selector of cfg in the store shared under name, creating them from
+// cfg if they do not exist yet. The caller must release the service once it is no longer used.
+func acquireSharedService(name string, cfg Configuration) (*sharedService, error) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	store, ok := sharedStores[name]
+	if !ok {
+		var err error
+		if store, err = newSharedStore(name, cfg); err != nil {
+			return nil, err
+		}
+		sharedStores[name] = store
+	} else if source := sourceIdentity(cfg); source != store.source {
+		// the connection is created from the configuration of the first handle, it cannot serve other sources
+		return nil, fmt.Errorf("shared in-process service %q syncs from %s, not %s", name, store.source, source)
+	} else if funcPointer(cfg.HeaderProvider) != store.headerProvider {
+		return nil, fmt.Errorf("shared in-process service %q uses another header provider", name)
+	}
+
+	shared, ok := store.services[cfg.Selector]
+	if !ok {
+		cfg.connection = store.connection
+		shared = &sharedService{
+			store:    store,
+			selector: cfg.Selector,
+			settings: serviceSettings(cfg),
+			service:  NewInProcessService(cfg),
+			stopped:  make(chan struct{}),
+			handles:  map[*SharedService]chan struct{}{},
+		}
+		store.services[cfg.Selector] = shared
+	} else if settings := serviceSettings(cfg); settings != shared.settings {
+		// the service is created from the configuration of the first handle of the selector
+		return nil, fmt.Errorf("shared in-process service %q of selector %q is configured with %s, not %s",
+			name, cfg.Selector, shared.settings, settings)
+	}
+
+	shared.refs++
+	return shared, nil
+}
+
+// release drops a reference to the service, the last reference shuts the service down. The shared connection is
+// closed with the last service of the store.
+func (s *sharedService) release() {
+	sharedMu.Lock()
+	s.refs--
+	last := s.refs == 0
+	var connection *googlegrpc.ClientConn
+	if last {
+		delete(s.store.services, s.selector)
+		if len(s.store.services) == 0 {
+			delete(sharedStores, s.store.name)
+			connection = s.store.connection
+		}
+	}
+	sharedMu.Unlock()
+
+	if last {
+		close(s.stopped)
+		s.service.Shutdown()
+	}
+	if connection != nil {
+		if err := connection.Close(); err != nil {
+			s.service.logger.Warn(fmt.Sprintf("failed to close the connection of shared in-process service %q: %v",
+				s.store.name, err))
+		}
+	}
+}
+
+// usesGrpcSource reports whether the service syncs from the gRPC sync service
+func usesGrpcSource(cfg Configuration) bool {
+	if len(cfg.Sources) > 0 {
+		return slices.ContainsFunc(cfg.Sources, func(source FlagSource) bool {
+			return source.Type == SourceGrpc
+		})
+	}
+	return cfg.CustomSyncProvider == nil && cfg.OfflineFlagSource == ""
+}
+
+// sourceIdentity describes the flag sources of the configuration and the settings of the gRPC connection. The dial
+// options overriding the connection settings can not be compared, only their number is.
+func sourceIdentity(cfg Configuration) string {
+	var sources []string
+	if len(cfg.Sources) > 0 {
+		for _, source := range cfg.Sources {
+			sources = append(sources, fmt.Sprintf("%s %s%s", source.Type, source.Path, source.Uri))
+		}
+	} else if cfg.CustomSyncProvider != nil {
+		sources = append(sources, "custom "+cfg.CustomSyncProviderUri)
+	} else if cfg.OfflineFlagSource != "" {
+		sources = append(sources, "file "+cfg.OfflineFlagSource)
+	}
+
+	if usesGrpcSource(cfg) {
+		sources = append(sources, fmt.Sprintf("grpc %s (tls: %t, certificate: %q, client certificate: %q, client key: %q, "+
+			"header provider: %t, insecure headers: %t, dial options: %d)",
+			buildGrpcUri(cfg), cfg.TLSEnabled, cfg.CertificatePath, cfg.ClientCertificatePath, cfg.ClientKeyPath,
+			cfg.HeaderProvider != nil, cfg.InsecureHeaders, len(cfg.GrpcDialOptionsOverride)))
+	}
+	return "[" + strings.Join(sources, ", ") + "]"
+}
+
+// serviceSettings describes the configuration of the service of a selector besides its sources. Operators are
+// compared by name, as a name can only be registered with a single function.
+func serviceSettings(cfg Configuration) string {
+	return fmt.Sprintf("(fatal status codes: %v, retry grace period: %ds, snapshot: %q, snapshot deadline: %s, "+
+		"operators: %v)", cfg.FatalStatusCodes, cfg.RetryGracePeriod, cfg.SnapshotPath, cfg.SnapshotDeadline,
+		slices.Sorted(maps.Keys(cfg.Operators)))
+}
+
+// funcPointer identifies a function, zero for nil
+func funcPointer(fn interface{}) uintptr {
+	value := reflect.ValueOf(fn)
+	if !value.IsValid() || value.IsNil() {
+		return 0
+	}
+	return value.Pointer()
+}
+
+// init initializes the service once, concurrent callers wait for the outcome
+func (s *sharedService) init() error {
+	s.initOnce.Do(func() {
+		go s.forwardEvents()
+		s.initErr = s.service.Init()
+	})
+	return s.initErr
+}
+
+// subscribe forwards the events of the service to the handle until done is closed, starting with the current state
+// of the service
+func (s *sharedService) subscribe(h *SharedService, done chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handles[h] = done
+	if s.state != nil {
+		select {
+		case h.events <- *s.state:
+		default:
+		}
+	}
+}
+
+func (s *sharedService) unsubscribe(h *SharedService) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.handles, h)
+}
+
+// forwardEvents forwards the events of the service to all subscribed handles
+func (s *sharedService) forwardEvents() {
+	for {
+		select {
+		case <-s.stopped:
+			return
+		case event := <-s.service.EventChannel():
+			s.mu.Lock()
+			if event.EventType != of.ProviderConfigChange {
+				s.state = &event
+			}
+			handles := maps.Clone(s.handles)
+			s.mu.Unlock()
+
+			for h, done := range handles {
+				select {
+				case h.events <- event:
+				case <-done:
+				case <-s.stopped:
+				}
+			}
+		}
+	}
+}
+
+// SharedService is a handle to an in-process service shared with the other handles of the same name.
+// Only handles of the same selector share the sync stream and the flag store of the service, handles of other
+// selectors only share the gRPC connection, as the flag store can not hold the flags of several selectors. A service
+// is created from the configuration of the first handle of its selector which is initialized, and shut down once all
+// its handles are shut down. Handles sharing a name must sync from the same flag sources with the same connection
+// settings, the connection is created from the configuration of the first handle. Handles of the same selector must
+// also configure the service the same way.
+type SharedService struct {
+	name   string
+	cfg    Configuration
+	events chan of.Event
+
+	mu     sync.RWMutex
+	shared *sharedService
+	done   chan struct{}
+}
+
+// NewSharedInProcessService creates a handle to the in-process service shared under name
+func NewSharedInProcessService(name string, cfg Configuration) *SharedService {
+	return &SharedService{
+		name:   name,
+		cfg:    cfg,
+		events: make(chan of.Event, eventChannelBuffer),
+	}
+}
+
+// Init initializes the shared service, unless another handle already did
+func (h *SharedService) Init() error {
+	shared, err := acquireSharedService(h.name, h.cfg)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	h.mu.Lock()
+	h.shared = shared
+	h.done = done
+	h.mu.Unlock()
+
+	shared.subscribe(h, done)
+	if err := shared.init(); err != nil {
+		h.Shutdown()
+		return err
+	}
+	return nil
+}
+
+// Shutdown releases the shared service, the last handle shuts it down
+func (h *SharedService) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.done == nil {
+		return
+	}
+	close(h.done)
+	h.done = nil
+
+	h.shared.unsubscribe(h)
+	h.shared.release()
+}
+
+// EventChannel returns the events of the shared service
+func (h *SharedService) EventChannel() <-chan of.Event {
+	return h.events
+}
+
+// service returns the shared service, or nil if the handle was not initialized yet
+func (h *SharedService) service() *InProcess {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.shared == nil {
+		return nil
+	}
+	return h.shared.service
+}
+
+// notInitialized is the resolution detail of evaluations before the handle was initialized
+func notInitialized() of.ProviderResolutionDetail {
+	return of.ProviderResolutionDetail{
+		ResolutionError: of.NewProviderNotReadyResolutionError("shared in-process service is not initialized"),
+		Reason:          of.ErrorReason,
+	}
+}
+
+// ResolveBoolean resolves a boolean flag value
+func (h *SharedService) ResolveBoolean(ctx context.Context, key string, defaultValue bool, evalCtx map[string]interface{}) of.BoolResolutionDetail {
+	service := h.service()
+	if service == nil {
+		return of.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notInitialized()}
+	}
+	return service.ResolveBoolean(ctx, key, defaultValue, evalCtx)
+}
+
+// ResolveString resolves a string flag value
+func (h *SharedService) ResolveString(ctx context.Context, key string, defaultValue string, evalCtx map[string]interface{}) of.StringResolutionDetail {
+	service := h.service()
+	if service == nil {
+		return of.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notInitialized()}
+	}
+	return service.ResolveString(ctx, key, defaultValue, evalCtx)
+}
+
+// ResolveFloat resolves a float flag value
+func (h *SharedService) ResolveFloat(ctx context.Context, key string, defaultValue float64, evalCtx map[string]interface{}) of.FloatResolutionDetail {
+	service := h.service()
+	if service == nil {
+		return of.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notInitialized()}
+	}
+	return service.ResolveFloat(ctx, key, defaultValue, evalCtx)
+}
+
+// ResolveInt resolves an int flag value
+func (h *SharedService) ResolveInt(ctx context.Context, key string, defaultValue int64, evalCtx map[string]interface{}) of.IntResolutionDetail {
+	service := h.service()
+	if service == nil {
+		return of.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notInitialized()}
+	}
+	return service.ResolveInt(ctx, key, defaultValue, evalCtx)
+}
+
+// ResolveObject resolves an object flag value
+func (h *SharedService) ResolveObject(ctx context.Context, key string, defaultValue interface{}, evalCtx map[string]interface{}) of.InterfaceResolutionDetail {
+	service := h.service()
+	if service == nil {
+		return of.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notInitialized()}
+	}
+	return service.ResolveObject(ctx, key, defaultValue, evalCtx)
+}
+
+// Resolve resolves a single flag regardless of its type
+func (h *SharedService) Resolve(ctx context.Context, key string, evalCtx map[string]interface{}) of.InterfaceResolutionDetail {
+	service := h.service()
+	if service == nil {
+		return of.InterfaceResolutionDetail{ProviderResolutionDetail: notInitialized()}
+	}
+	return service.Resolve(ctx, key, evalCtx)
+}
+
+// ResolveAll resolves all flags
+func (h *SharedService) ResolveAll(ctx context.Context, evalCtx map[string]interface{}) (
+	map[string]of.InterfaceResolutionDetail, error,
+) {
+	service := h.service()
+	if service == nil {
+		return nil, of.NewProviderNotReadyResolutionError("shared in-process service is not initialized")
+	}
+	return service.ResolveAll(ctx, evalCtx)
+}
@@ -0,0 +1,211 @@
+package process
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// awaitSharedEvent waits for an event of the given type of the handle, skipping other events
+func awaitSharedEvent(t *testing.T, handle *SharedService, eventType of.EventType) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-handle.EventChannel():
+			if event.EventType == eventType {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s event", eventType)
+		}
+	}
+}
+
+func isShared(name string) bool {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	_, ok := sharedStores[name]
+	return ok
+}
+
+func TestSharedInProcessService(t *testing.T) {
+	// given
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, offlinePath, []byte(validFlags))
+
+	name := t.Name()
+	first := NewSharedInProcessService(name, Configuration{OfflineFlagSource: offlinePath})
+	second := NewSharedInProcessService(name, Configuration{OfflineFlagSource: offlinePath})
+
+	detail := first.ResolveBoolean(t.Context(), "myBoolFlag", false, map[string]interface{}{})
+	if detail.ResolutionDetail().ErrorCode != of.ProviderNotReadyCode {
+		t.Errorf("expected %s before initialization, got %s", of.ProviderNotReadyCode, detail.ResolutionDetail().ErrorCode)
+	}
+
+	// when
+	if err := first.Init(); err != nil {
+		t.Fatal(err)
+	}
+	awaitSharedEvent(t, first, of.ProviderReady)
+
+	if err := second.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// then
+	// the handle joining later observes the current state of the shared service
+	awaitSharedEvent(t, second, of.ProviderReady)
+
+	if first.service() != second.service() {
+		t.Fatal("expected the handles to share the service")
+	}
+
+	for _, handle := range []*SharedService{first, second} {
+		detail := handle.ResolveBoolean(t.Context(), "myBoolFlag", false, map[string]interface{}{})
+		if !detail.Value {
+			t.Errorf("expected true, got false")
+		}
+	}
+
+	// flag changes are reported to all handles
+	writeFile(t, offlinePath, []byte(strings.Replace(validFlags, `"defaultVariant": "on"`, `"defaultVariant": "off"`, 1)))
+	awaitSharedEvent(t, first, of.ProviderConfigChange)
+	awaitSharedEvent(t, second, of.ProviderConfigChange)
+
+	// the shared service is shut down with the last handle
+	first.Shutdown()
+	if !isShared(name) {
+		t.Fatal("expected the service to remain shared while referenced")
+	}
+	detail = second.ResolveBoolean(t.Context(), "myBoolFlag", true, map[string]interface{}{})
+	if detail.Value {
+		t.Errorf("expected the updated flag configuration")
+	}
+
+	second.Shutdown()
+	if isShared(name) {
+		t.Error("expected the service to be released")
+	}
+
+	// a handle initialized again creates a new shared service
+	if err := first.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(first.Shutdown)
+	awaitSharedEvent(t, first, of.ProviderReady)
+}
+
+func TestSharedInProcessServiceSelectors(t *testing.T) {
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, offlinePath, []byte(validFlags))
+
+	name := t.Name()
+	first := NewSharedInProcessService(name, Configuration{OfflineFlagSource: offlinePath, Selector: "app-a"})
+	second := NewSharedInProcessService(name, Configuration{OfflineFlagSource: offlinePath, Selector: "app-b"})
+	for _, handle := range []*SharedService{first, second} {
+		if err := handle.Init(); err != nil {
+			t.Fatal(err)
+		}
+		awaitSharedEvent(t, handle, of.ProviderReady)
+	}
+
+	// every selector is synced by a service of its own
+	if first.service() == second.service() {
+		t.Fatal("expected the handles of different selectors to use different services")
+	}
+	detail := second.ResolveBoolean(t.Context(), "myBoolFlag", false, map[string]interface{}{})
+	if detail.FlagMetadata["scope"] != "app-b" {
+		t.Errorf("expected the scope of the selector, got %v", detail.FlagMetadata["scope"])
+	}
+
+	first.Shutdown()
+	if !isShared(name) {
+		t.Fatal("expected the store to remain shared while referenced")
+	}
+	second.Shutdown()
+	if isShared(name) {
+		t.Error("expected the store to be released")
+	}
+}
+
+func TestSharedInProcessServiceConnection(t *testing.T) {
+	name := t.Name()
+	first, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8015, Selector: "app-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8015, Selector: "app-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the streams of the selectors share the connection of the store
+	connection := first.store.connection
+	if connection == nil {
+		t.Fatal("expected a shared connection")
+	}
+	for _, shared := range []*sharedService{first, second} {
+		if syncProvider := shared.service.syncProvider.(*Sync); syncProvider.sharedConnection != connection {
+			t.Errorf("expected the sync of selector %q to use the shared connection", shared.selector)
+		}
+	}
+
+	// handles connecting elsewhere are rejected rather than served by the connection of the store
+	if _, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8016, Selector: "app-a"}); err == nil {
+		t.Error("expected a handle with another port to be rejected")
+	}
+	if _, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8015, TLSEnabled: true}); err == nil {
+		t.Error("expected a handle with another TLS configuration to be rejected")
+	}
+	headerProvider := func(context.Context) map[string]string { return nil }
+	if _, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8015, HeaderProvider: headerProvider, InsecureHeaders: true}); err == nil {
+		t.Error("expected a handle with a header provider to be rejected")
+	}
+	if _, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8015, GrpcDialOptionsOverride: []grpc.DialOption{grpc.WithUserAgent("test")}}); err == nil {
+		t.Error("expected a handle with dial options to be rejected")
+	}
+
+	// the service of a selector is created from the configuration of its first handle
+	if _, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8015, Selector: "app-a", FatalStatusCodes: []string{"UNAUTHENTICATED"}}); err == nil {
+		t.Error("expected a handle with other fatal status codes to be rejected")
+	}
+	if _, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8015, Selector: "app-a", SnapshotPath: "snapshot.json"}); err == nil {
+		t.Error("expected a handle with another snapshot to be rejected")
+	}
+	if _, err := acquireSharedService(name, Configuration{Host: "localhost", Port: 8015, Selector: "app-a", Operators: map[string]Operator{"cidr": cidr}}); err == nil {
+		t.Error("expected a handle with other operators to be rejected")
+	}
+
+	first.release()
+	if connection.GetState() == connectivity.Shutdown {
+		t.Fatal("expected the connection to remain open while the store is referenced")
+	}
+	second.release()
+	if connection.GetState() != connectivity.Shutdown {
+		t.Error("expected the connection to be closed with the store")
+	}
+}
+
+func TestSharedInProcessServiceInitializationFailure(t *testing.T) {
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, offlinePath, []byte(`{"flags": {"myBoolFlag": {"state": "UNKNOWN"}}}`))
+
+	name := t.Name()
+	handle := NewSharedInProcessService(name, Configuration{OfflineFlagSource: offlinePath})
+	if err := handle.Init(); err == nil {
+		t.Fatal("expected initialization to fail")
+	}
+
+	if isShared(name) {
+		t.Error("expected the service of a failed initialization to be released")
+	}
+}
@@ -0,0 +1,61 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// snapshotFileMode restricts snapshot access to the owner, flag configurations may contain sensitive targeting data
+const snapshotFileMode = 0o600
+
+// writeSnapshot atomically replaces the snapshot at path with the given flag configurations, keyed by their source.
+// The snapshot is written to a temporary file in the same directory first, so a crash never leaves a partially
+// written snapshot behind.
+func writeSnapshot(path string, flagData map[string]string) error {
+	data, err := json.Marshal(flagData)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary snapshot file: %w", err)
+	}
+	defer func() {
+		// no-op once the temporary file has been renamed
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Chmod(snapshotFileMode); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to set snapshot permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return nil
+}
+
+// readSnapshot reads the flag configurations of the snapshot at path, keyed by their source
+func readSnapshot(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var flagData map[string]string
+	if err := json.Unmarshal(data, &flagData); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return flagData, nil
+}
@@ -1,43 +1,90 @@
 package rpc
 
-import "time"
+import (
+	"math/rand/v2"
+	"time"
+)
 
 const (
-	defaultDelay = time.Second
-	factor       = 2
+	defaultDelay    = time.Second
+	defaultMaxDelay = 2 * time.Minute
+	factor          = 2
 )
 
 type retryCounter struct {
 	baseRetryDelay time.Duration
+	maxRetryDelay  time.Duration
+	multiplier     float64
+	jitter         float64
 	maxRetries     int
+	infinite       bool
 
 	currentDelay   time.Duration
 	currentRetries int
 }
 
-func newRetryCounter(maxRetries int) retryCounter {
-	return retryCounter{
-		baseRetryDelay: defaultDelay,
+func newRetryCounter(cfg Configuration, maxRetries int) retryCounter {
+	c := retryCounter{
+		baseRetryDelay: cfg.RetryBackoff,
+		maxRetryDelay:  cfg.RetryBackoffMax,
+		multiplier:     cfg.RetryBackoffMultiplier,
+		jitter:         cfg.RetryBackoffJitter,
 		maxRetries:     maxRetries,
+		infinite:       cfg.InfiniteRetries,
+	}
+
+	if c.baseRetryDelay <= 0 {
+		c.baseRetryDelay = defaultDelay
+	}
+
+	if c.maxRetryDelay <= 0 {
+		c.maxRetryDelay = defaultMaxDelay
 	}
+
+	if c.multiplier < 1 {
+		c.multiplier = factor
+	}
+
+	c.jitter = min(max(c.jitter, 0), 1)
+
+	return c
 }
 
 // reset the retry counter and sleep delay
 func (c *retryCounter) reset() {
-	c.currentDelay = defaultDelay
+	c.currentDelay = c.baseRetryDelay
 	c.currentRetries = 0
 }
 
 // retry increments current retry attempts, check and return a boolean stating retry is allowed
 func (c *retryCounter) retry() bool {
 	c.currentRetries++
-	return c.currentRetries <= c.maxRetries
+	return c.infinite || c.currentRetries <= c.maxRetries
 }
 
-// sleep returns the current sleep delay and increment the next sleep value
+// exhaustedNow returns true if the latest retry attempt was the first one exceeding the maximum retries
+func (c *retryCounter) exhaustedNow() bool {
+	return !c.infinite && c.currentRetries == c.maxRetries+1
+}
+
+// sleep returns the current sleep delay with jitter applied and increment the next sleep value up to the maximum delay
 func (c *retryCounter) sleep() time.Duration {
+	if c.currentDelay <= 0 {
+		c.currentDelay = c.baseRetryDelay
+	}
+
 	var value = c.currentDelay
-	c.currentDelay = factor * c.currentDelay
+
+	next := time.Duration(float64(c.currentDelay) * max(c.multiplier, 1))
+	if c.maxRetryDelay > 0 && next > c.maxRetryDelay {
+		next = max(c.maxRetryDelay, value)
+	}
+	c.currentDelay = next
+
+	if c.jitter > 0 {
+		// spread the delay uniformly within [value * (1 - jitter), value * (1 + jitter)]
+		value = time.Duration(float64(value) * (1 - c.jitter + 2*c.jitter*rand.Float64()))
+	}
 
 	return value
 }
@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryCounterBackoff(t *testing.T) {
+	counter := newRetryCounter(Configuration{
+		RetryBackoff:           100 * time.Millisecond,
+		RetryBackoffMax:        time.Second,
+		RetryBackoffMultiplier: 3,
+	}, 2)
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		300 * time.Millisecond,
+		900 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+
+	for i, want := range expected {
+		if got := counter.sleep(); got != want {
+			t.Errorf("attempt %d: expected delay %v, got %v", i, want, got)
+		}
+	}
+
+	counter.reset()
+	if got := counter.sleep(); got != 100*time.Millisecond {
+		t.Errorf("expected delay to be reset to %v, got %v", 100*time.Millisecond, got)
+	}
+}
+
+func TestRetryCounterJitter(t *testing.T) {
+	counter := newRetryCounter(Configuration{
+		RetryBackoff:           time.Second,
+		RetryBackoffMultiplier: 1,
+		RetryBackoffJitter:     0.5,
+	}, 1)
+
+	for i := 0; i < 100; i++ {
+		if got := counter.sleep(); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("expected jittered delay within [500ms, 1.5s], got %v", got)
+		}
+	}
+}
+
+func TestRetryCounterAttempts(t *testing.T) {
+	counter := newRetryCounter(Configuration{}, 2)
+
+	for i := 0; i < 2; i++ {
+		if !counter.retry() {
+			t.Fatalf("expected attempt %d to be allowed", i+1)
+		}
+	}
+
+	if counter.retry() || !counter.exhaustedNow() {
+		t.Errorf("expected retries to be exhausted after the third attempt")
+	}
+
+	if counter.retry() || counter.exhaustedNow() {
+		t.Errorf("expected exhaustion to be reported only once")
+	}
+
+	infinite := newRetryCounter(Configuration{InfiniteRetries: true}, 0)
+	for i := 0; i < 10; i++ {
+		if !infinite.retry() {
+			t.Fatalf("expected infinite retries, but attempt %d was denied", i+1)
+		}
+	}
+}
@@ -16,10 +16,11 @@ import (
 	"connectrpc.com/connect"
 	"connectrpc.com/otelconnect"
 	"github.com/go-logr/logr"
-	flagdModels "github.com/open-feature/flagd/core/pkg/model"
 	flagdService "github.com/open-feature/flagd/core/pkg/service"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/certificate"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/logger"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/stale"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"golang.org/x/net/context"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -28,10 +29,20 @@ import (
 const (
 	ReasonCached      = "CACHED"
 	ClientNotReadyMsg = "client did not yet finish the initialization"
+	// ReasonTimeout is the reason of evaluations exceeding their deadline. The OpenFeature SDK defines no TIMEOUT error
+	// code and resolution errors can only be created with its standard codes, so timeouts have the GENERAL error code
+	// and are told apart by this reason and the wrapped errors.
+	ReasonTimeout = "TIMEOUT"
 )
 
+// defaultPrefetchTimeout bounds prefetching without request timeout, events wait for prefetching to complete
+const defaultPrefetchTimeout = 5 * time.Second
+
 var ErrClientNotReady = of.NewProviderNotReadyResolutionError(ClientNotReadyMsg)
 
+// ErrRequestTimeout is wrapped by the resolution errors of evaluations exceeding the configured request timeout
+var ErrRequestTimeout = errors.New("flag evaluation exceeded its deadline")
+
 type Configuration struct {
 	Port            uint16
 	Host            string
@@ -39,6 +50,34 @@ type Configuration struct {
 	SocketPath      string
 	TLSEnabled      bool
 	OtelInterceptor bool
+
+	// client certificate and key presented for mutual TLS, reloaded when the files are rotated
+	ClientCertificatePath string
+	ClientKeyPath         string
+
+	// HeaderProvider returns headers, e.g. an authorization token, added to every call to flagd
+	HeaderProvider func(ctx context.Context) map[string]string
+	// InsecureHeaders allows the headers to be sent over plaintext connections, e.g. to a sidecar terminating TLS
+	InsecureHeaders bool
+
+	// event stream reconnection backoff, zero values fall back to the defaults
+	RetryBackoff           time.Duration
+	RetryBackoffMax        time.Duration
+	RetryBackoffMultiplier float64
+	RetryBackoffJitter     float64
+	InfiniteRetries        bool
+
+	// RetryGracePeriod is the time window in seconds for the transition from stale to error state
+	RetryGracePeriod int
+
+	// Prefetch warms the cache with a bulk evaluation for PrefetchContext whenever the service becomes ready and
+	// whenever flags change, so evaluations after a change are served from the cache. The bulk evaluation is bounded
+	// by RequestTimeout, or by defaultPrefetchTimeout if there is no request timeout.
+	Prefetch        bool
+	PrefetchContext map[string]interface{}
+
+	// RequestTimeout bounds evaluations whose context has no deadline, zero leaves them unbounded
+	RequestTimeout time.Duration
 }
 
 // Service handles the client side  interface for the flagd server
@@ -53,6 +92,10 @@ type Service struct {
 	cancelHook  context.CancelFunc
 	wg          sync.WaitGroup
 	streamReady chan error // Channel to signal when event stream is connected
+
+	// stale is set while the connection to a previously connected event stream is lost
+	stale      bool
+	staleTimer stale.Timer
 }
 
 func NewService(cfg Configuration, cache *cache.Service, logger logr.Logger, retries int) *Service {
@@ -62,7 +105,7 @@ func NewService(cfg Configuration, cache *cache.Service, logger logr.Logger, ret
 		cfg:          cfg,
 		events:       make(chan of.Event, 1),
 		logger:       logger,
-		retryCounter: newRetryCounter(retries),
+		retryCounter: newRetryCounter(cfg, retries),
 		streamReady:  make(chan error, 1),
 	}
 }
@@ -105,14 +148,16 @@ func (s *Service) Shutdown() {
 		s.cancelHook()
 	}
 	s.wg.Wait()
+	s.staleTimer.Stop()
 }
 
 // ResolveBoolean handles the flag evaluation response from the flagd ResolveBoolean rpc
 func (s *Service) ResolveBoolean(ctx context.Context, key string, defaultValue bool,
 	evalCtx map[string]interface{}) of.BoolResolutionDetail {
 
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	cacheKey, cacheable := s.cache.Key(key, evalCtx)
+	if cacheable {
+		fromCache, ok := s.cache.GetCache().Get(cacheKey)
 		if ok {
 			fromCacheResDetail, ok := fromCache.(of.BoolResolutionDetail)
 			if ok {
@@ -133,7 +178,7 @@ func (s *Service) ResolveBoolean(ctx context.Context, key string, defaultValue b
 
 	var e of.ResolutionError
 	resp, err := resolve[schemaV1.ResolveBooleanRequest, schemaV1.ResolveBooleanResponse](
-		ctx, s.logger, s.client.ResolveBoolean, key, evalCtx,
+		ctx, s.logger, s.cfg.RequestTimeout, s.client.ResolveBoolean, key, evalCtx,
 	)
 	if err != nil {
 		if !errors.As(err, &e) {
@@ -144,7 +189,7 @@ func (s *Service) ResolveBoolean(ctx context.Context, key string, defaultValue b
 			Value: defaultValue,
 			ProviderResolutionDetail: of.ProviderResolutionDetail{
 				ResolutionError: e,
-				Reason:          of.ErrorReason,
+				Reason:          errorReason(e),
 			},
 		}
 	}
@@ -159,8 +204,8 @@ func (s *Service) ResolveBoolean(ctx context.Context, key string, defaultValue b
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
+	if cacheable && s.cache.ShouldCache(string(detail.Reason)) {
+		s.cache.GetCache().Add(cacheKey, detail)
 	}
 
 	return detail
@@ -170,8 +215,9 @@ func (s *Service) ResolveBoolean(ctx context.Context, key string, defaultValue b
 func (s *Service) ResolveString(ctx context.Context, key string, defaultValue string,
 	evalCtx map[string]interface{}) of.StringResolutionDetail {
 
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	cacheKey, cacheable := s.cache.Key(key, evalCtx)
+	if cacheable {
+		fromCache, ok := s.cache.GetCache().Get(cacheKey)
 		if ok {
 			fromCacheResDetail, ok := fromCache.(of.StringResolutionDetail)
 			if ok {
@@ -192,7 +238,7 @@ func (s *Service) ResolveString(ctx context.Context, key string, defaultValue st
 
 	var e of.ResolutionError
 	resp, err := resolve[schemaV1.ResolveStringRequest, schemaV1.ResolveStringResponse](
-		ctx, s.logger, s.client.ResolveString, key, evalCtx,
+		ctx, s.logger, s.cfg.RequestTimeout, s.client.ResolveString, key, evalCtx,
 	)
 	if err != nil {
 		if !errors.As(err, &e) {
@@ -203,7 +249,7 @@ func (s *Service) ResolveString(ctx context.Context, key string, defaultValue st
 			Value: defaultValue,
 			ProviderResolutionDetail: of.ProviderResolutionDetail{
 				ResolutionError: e,
-				Reason:          of.ErrorReason,
+				Reason:          errorReason(e),
 			},
 		}
 	}
@@ -218,8 +264,8 @@ func (s *Service) ResolveString(ctx context.Context, key string, defaultValue st
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
+	if cacheable && s.cache.ShouldCache(string(detail.Reason)) {
+		s.cache.GetCache().Add(cacheKey, detail)
 	}
 
 	return detail
@@ -229,8 +275,9 @@ func (s *Service) ResolveString(ctx context.Context, key string, defaultValue st
 func (s *Service) ResolveFloat(ctx context.Context, key string, defaultValue float64,
 	evalCtx map[string]interface{}) of.FloatResolutionDetail {
 
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	cacheKey, cacheable := s.cache.Key(key, evalCtx)
+	if cacheable {
+		fromCache, ok := s.cache.GetCache().Get(cacheKey)
 		if ok {
 			fromCacheResDetail, ok := fromCache.(of.FloatResolutionDetail)
 			if ok {
@@ -251,7 +298,7 @@ func (s *Service) ResolveFloat(ctx context.Context, key string, defaultValue flo
 
 	var e of.ResolutionError
 	resp, err := resolve[schemaV1.ResolveFloatRequest, schemaV1.ResolveFloatResponse](
-		ctx, s.logger, s.client.ResolveFloat, key, evalCtx,
+		ctx, s.logger, s.cfg.RequestTimeout, s.client.ResolveFloat, key, evalCtx,
 	)
 	if err != nil {
 		if !errors.As(err, &e) {
@@ -262,7 +309,7 @@ func (s *Service) ResolveFloat(ctx context.Context, key string, defaultValue flo
 			Value: defaultValue,
 			ProviderResolutionDetail: of.ProviderResolutionDetail{
 				ResolutionError: e,
-				Reason:          of.ErrorReason,
+				Reason:          errorReason(e),
 			},
 		}
 	}
@@ -277,8 +324,8 @@ func (s *Service) ResolveFloat(ctx context.Context, key string, defaultValue flo
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
+	if cacheable && s.cache.ShouldCache(string(detail.Reason)) {
+		s.cache.GetCache().Add(cacheKey, detail)
 	}
 
 	return detail
@@ -288,8 +335,9 @@ func (s *Service) ResolveFloat(ctx context.Context, key string, defaultValue flo
 func (s *Service) ResolveInt(ctx context.Context, key string, defaultValue int64,
 	evalCtx map[string]interface{}) of.IntResolutionDetail {
 
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	cacheKey, cacheable := s.cache.Key(key, evalCtx)
+	if cacheable {
+		fromCache, ok := s.cache.GetCache().Get(cacheKey)
 		if ok {
 			fromCacheResDetail, ok := fromCache.(of.IntResolutionDetail)
 			if ok {
@@ -310,7 +358,7 @@ func (s *Service) ResolveInt(ctx context.Context, key string, defaultValue int64
 
 	var e of.ResolutionError
 	resp, err := resolve[schemaV1.ResolveIntRequest, schemaV1.ResolveIntResponse](
-		ctx, s.logger, s.client.ResolveInt, key, evalCtx,
+		ctx, s.logger, s.cfg.RequestTimeout, s.client.ResolveInt, key, evalCtx,
 	)
 	if err != nil {
 		if !errors.As(err, &e) {
@@ -321,7 +369,7 @@ func (s *Service) ResolveInt(ctx context.Context, key string, defaultValue int64
 			Value: defaultValue,
 			ProviderResolutionDetail: of.ProviderResolutionDetail{
 				ResolutionError: e,
-				Reason:          of.ErrorReason,
+				Reason:          errorReason(e),
 			},
 		}
 	}
@@ -336,8 +384,8 @@ func (s *Service) ResolveInt(ctx context.Context, key string, defaultValue int64
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
+	if cacheable && s.cache.ShouldCache(string(detail.Reason)) {
+		s.cache.GetCache().Add(cacheKey, detail)
 	}
 
 	return detail
@@ -346,8 +394,9 @@ func (s *Service) ResolveInt(ctx context.Context, key string, defaultValue int64
 // ResolveObject handles the flag evaluation response from the  flagd interface ResolveObject rpc
 func (s *Service) ResolveObject(ctx context.Context, key string, defaultValue interface{},
 	evalCtx map[string]interface{}) of.InterfaceResolutionDetail {
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	cacheKey, cacheable := s.cache.Key(key, evalCtx)
+	if cacheable {
+		fromCache, ok := s.cache.GetCache().Get(cacheKey)
 		if ok {
 			fromCacheResDetail, ok := fromCache.(of.InterfaceResolutionDetail)
 			if ok {
@@ -368,7 +417,7 @@ func (s *Service) ResolveObject(ctx context.Context, key string, defaultValue in
 
 	var e of.ResolutionError
 	resp, err := resolve[schemaV1.ResolveObjectRequest, schemaV1.ResolveObjectResponse](
-		ctx, s.logger, s.client.ResolveObject, key, evalCtx,
+		ctx, s.logger, s.cfg.RequestTimeout, s.client.ResolveObject, key, evalCtx,
 	)
 	if err != nil {
 		if !errors.As(err, &e) {
@@ -379,7 +428,7 @@ func (s *Service) ResolveObject(ctx context.Context, key string, defaultValue in
 			Value: defaultValue,
 			ProviderResolutionDetail: of.ProviderResolutionDetail{
 				ResolutionError: e,
-				Reason:          of.ErrorReason,
+				Reason:          errorReason(e),
 			},
 		}
 	}
@@ -394,19 +443,73 @@ func (s *Service) ResolveObject(ctx context.Context, key string, defaultValue in
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
+	if cacheable && s.cache.ShouldCache(string(detail.Reason)) {
+		s.cache.GetCache().Add(cacheKey, detail)
 	}
 
 	return detail
 }
 
+// ResolveAll evaluates all flags for the evaluation context with a single call to flagd
+func (s *Service) ResolveAll(ctx context.Context, evalCtx map[string]interface{}) (
+	map[string]of.InterfaceResolutionDetail, error) {
+	if !s.isInitialised() {
+		return nil, ErrClientNotReady
+	}
+
+	evalCtxF, err := structpb.NewStruct(evalCtx)
+	if err != nil {
+		s.logger.Error(err, "struct from evaluation context")
+		return nil, of.NewParseErrorResolutionError(err.Error())
+	}
+
+	ctx, cancel, bounded := withRequestTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+
+	res, err := s.client.ResolveAll(ctx, connect.NewRequest(&schemaV1.ResolveAllRequest{
+		Context: evalCtxF,
+	}))
+	if err != nil {
+		return nil, handleError(err, bounded)
+	}
+
+	details := make(map[string]of.InterfaceResolutionDetail, len(res.Msg.Flags))
+	for key, flag := range res.Msg.Flags {
+		details[key] = of.InterfaceResolutionDetail{
+			Value: anyFlagValue(flag),
+			ProviderResolutionDetail: of.ProviderResolutionDetail{
+				Reason:       of.Reason(flag.Reason),
+				Variant:      flag.Variant,
+				FlagMetadata: flag.Metadata.AsMap(),
+			},
+		}
+	}
+
+	return details, nil
+}
+
+// anyFlagValue converts the value of a bulk evaluation to bool, string, float64 or map[string]interface{}
+func anyFlagValue(flag *schemaV1.AnyFlag) interface{} {
+	switch value := flag.Value.(type) {
+	case *schemaV1.AnyFlag_BoolValue:
+		return value.BoolValue
+	case *schemaV1.AnyFlag_StringValue:
+		return value.StringValue
+	case *schemaV1.AnyFlag_DoubleValue:
+		return value.DoubleValue
+	case *schemaV1.AnyFlag_ObjectValue:
+		return value.ObjectValue.AsMap()
+	default:
+		return nil
+	}
+}
+
 func (s *Service) isInitialised() bool {
 	return s.client != nil
 }
 
 func resolve[req resolutionRequestConstraints, resp resolutionResponseConstraints](
-	ctx context.Context, logger logr.Logger,
+	ctx context.Context, logger logr.Logger, timeout time.Duration,
 	resolver func(context.Context, *connect.Request[req]) (*connect.Response[resp], error),
 	flagKey string, evalCtx map[string]interface{},
 ) (*resp, error) {
@@ -416,20 +519,43 @@ func resolve[req resolutionRequestConstraints, resp resolutionResponseConstraint
 		return nil, of.NewParseErrorResolutionError(err.Error())
 	}
 
+	ctx, cancel, bounded := withRequestTimeout(ctx, timeout)
+	defer cancel()
+
 	res, err := resolver(ctx, connect.NewRequest(&req{
 		FlagKey: flagKey,
 		Context: evalCtxF,
 	}))
 	if err != nil {
-		return nil, handleError(err)
+		return nil, handleError(err, bounded)
 	}
 
 	return res.Msg, nil
 }
 
-func handleError(err error) of.ResolutionError {
+// withRequestTimeout bounds the context by the timeout, unless the caller already set a deadline. It reports whether
+// the context was bounded by the timeout.
+func withRequestTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc, bool) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return ctx, func() {}, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, true
+}
+
+// handleError maps the error of an evaluation to a resolution error. Exceeded deadlines have the GENERAL error code,
+// as the SDK has no timeout code, and wrap context.DeadlineExceeded, and ErrRequestTimeout if the deadline was the
+// configured request timeout.
+func handleError(err error, bounded bool) of.ResolutionError {
 	connectErr := &connect.Error{}
 	errors.As(err, &connectErr)
+	if connectErr.Code() == connect.CodeDeadlineExceeded || errors.Is(err, context.DeadlineExceeded) {
+		// the SDK has no timeout error code, the TIMEOUT reason and the wrapped errors tell timeouts apart
+		if bounded {
+			return of.NewGeneralResolutionError(fmt.Sprintf("%s: %v", ErrRequestTimeout, err), ErrRequestTimeout, context.DeadlineExceeded, err)
+		}
+		return of.NewGeneralResolutionError(err.Error(), context.DeadlineExceeded, err)
+	}
 	switch connectErr.Code() {
 	case connect.CodeUnavailable:
 		return of.NewProviderNotReadyResolutionError(ConnectionError)
@@ -443,18 +569,31 @@ func handleError(err error) of.ResolutionError {
 	return of.NewGeneralResolutionError(err.Error())
 }
 
+// errorReason returns the reason of a failed evaluation
+func errorReason(err error) of.Reason {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ReasonTimeout
+	}
+	return of.ErrorReason
+}
+
 func (s *Service) EventChannel() <-chan of.Event {
 	return s.events
 }
 
 // startEventStream - starts listening to flagd event stream with retries.
 // This contains blocking calls and busy wait backed retry attempts, hence must be called concurrently.
-// If retrying is exhausted, an event with openfeature.ProviderError will be emitted.
+// If retrying is exhausted, an event with openfeature.ProviderError will be emitted. Once the event stream was
+// connected, retrying continues in the background until the service is shut down.
 func (s *Service) startEventStream(ctx context.Context) {
 	streamReadySignaled := false
-	
+
 	// wraps connection with retry attempts
-	for s.retryCounter.retry() {
+	for {
+		if !s.retryCounter.retry() && !s.handleRetriesExhausted(ctx, streamReadySignaled) {
+			return
+		}
+
 		s.logger.V(logger.Debug).Info("connecting to event stream")
 		err := s.streamClient(ctx, &streamReadySignaled)
 		if err != nil {
@@ -468,10 +607,10 @@ func (s *Service) startEventStream(ctx context.Context) {
 				return
 			}
 
-			// error in stream handler, purge cache if available and retry
+			// error in stream handler, retry while serving cached values for the grace period
 			s.logger.V(logger.Warn).Info(fmt.Sprintf("connection to event stream failed (%q), attempting again", err))
-			if s.cache.IsEnabled() {
-				s.cache.GetCache().Purge()
+			if streamReadySignaled {
+				s.handleConnectionLost(ctx, err)
 			}
 		}
 
@@ -484,16 +623,27 @@ func (s *Service) startEventStream(ctx context.Context) {
 		case <-time.After(s.retryCounter.sleep()):
 		}
 	}
+}
+
+// handleRetriesExhausted emits an error event once the retry attempts are exhausted. Returns false if retrying must
+// stop, which is the case if the event stream was never connected and hence the initialization failed.
+func (s *Service) handleRetriesExhausted(ctx context.Context, streamReadySignaled bool) bool {
+	if streamReadySignaled && !s.retryCounter.exhaustedNow() {
+		// error already reported, keep retrying in the background
+		return true
+	}
 
-	// retry attempts exhausted. Disable cache and emit error event
-	s.cache.Disable()
 	connErr := fmt.Errorf("grpc connection establishment failed")
-	
+
 	// Signal error if we haven't signaled success yet
 	if !streamReadySignaled {
 		s.signalStreamReady(connErr)
 	}
-	
+
+	// the error is reported right away, cached values are no longer served
+	s.staleTimer.Stop()
+	s.purgeCache()
+
 	s.sendEvent(ctx, of.Event{
 		ProviderName: "flagd",
 		EventType:    of.ProviderError,
@@ -501,6 +651,12 @@ func (s *Service) startEventStream(ctx context.Context) {
 			Message: connErr.Error(),
 		},
 	})
+
+	if streamReadySignaled {
+		s.logger.V(logger.Warn).Info("event stream retry attempts exhausted, retrying in the background")
+	}
+
+	return streamReadySignaled
 }
 
 // signalStreamReady signals Init() that the event stream status is known
@@ -521,7 +677,7 @@ func (s *Service) streamClient(ctx context.Context, streamReadySignaled *bool) e
 	}
 
 	s.logger.V(logger.Info).Info("connected to event stream")
-	
+
 	// Signal successful connection to Init() - stream is now ready
 	if !*streamReadySignaled {
 		s.signalStreamReady(nil) // nil means success
@@ -547,14 +703,7 @@ func (s *Service) streamClient(ctx context.Context, streamReadySignaled *bool) e
 	}
 
 	if err := stream.Err(); err != nil {
-		s.sendEvent(ctx, of.Event{
-			ProviderName: "flagd",
-			EventType:    of.ProviderError,
-			ProviderEventDetails: of.ProviderEventDetails{
-				Message: fmt.Sprintf("stream error: %s", err.Error()),
-			},
-		})
-		return err
+		return fmt.Errorf("stream error: %w", err)
 	}
 
 	return nil
@@ -565,33 +714,24 @@ func (s *Service) handleConfigurationChangeEvent(ctx context.Context, event *sch
 		return
 	}
 
-	if event.Data == nil {
-		// purge cache and return
-		s.cache.GetCache().Purge()
-		return
-	}
-
-	flagsVal, ok := event.Data.AsMap()["flags"]
-	if !ok {
-		// purge cache and return
-		s.cache.GetCache().Purge()
-		return
-	}
-
-	flags, ok := flagsVal.(map[string]interface{})
+	flags, ok := changedFlags(event)
 	if !ok {
 		// purge cache and return
 		s.cache.GetCache().Purge()
+		s.prefetch(ctx)
 		return
 	}
 
 	keys := make([]string, len(flags))
 
 	for flagKey := range flags {
-		s.cache.GetCache().Remove(flagKey)
+		s.cache.Invalidate(flagKey)
 		keys = append(keys, flagKey)
 	}
 
+	// warm the cache before handlers of the event evaluate the changed flags
+	s.prefetch(ctx)
+
 	s.sendEvent(ctx, of.Event{
 		ProviderName: "flagd",
 		EventType:    of.ProviderConfigChange,
@@ -602,13 +742,118 @@ func (s *Service) handleConfigurationChangeEvent(ctx context.Context, event *sch
 	})
 }
 
+// handleConnectionLost emits a stale event when the connection to the event stream is lost and escalates to an
+// error event if the connection is not re-established within the grace period.
+func (s *Service) handleConnectionLost(ctx context.Context, err error) {
+	if s.stale {
+		return
+	}
+	s.stale = true
+
+	s.sendEvent(ctx, of.Event{
+		ProviderName: "flagd",
+		EventType:    of.ProviderStale,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message: err.Error(),
+		},
+	})
+
+	s.staleTimer.Start(time.Duration(s.cfg.RetryGracePeriod)*time.Second, func() {
+		// flag changes can no longer be tracked, stop serving cached values
+		s.purgeCache()
+		s.sendEvent(ctx, of.Event{
+			ProviderName: "flagd",
+			EventType:    of.ProviderError,
+			ProviderEventDetails: of.ProviderEventDetails{
+				Message: "connection to event stream not re-established within the grace period",
+			},
+		})
+	})
+}
+
 func (s *Service) handleReadyEvent(ctx context.Context) {
+	if s.stale {
+		// flag changes may have been missed while the connection was lost
+		s.stale = false
+		s.staleTimer.Stop()
+		s.purgeCache()
+	}
+	s.prefetch(ctx)
+
 	s.sendEvent(ctx, of.Event{
 		ProviderName: "flagd",
 		EventType:    of.ProviderReady,
 	})
 }
 
+// changedFlags returns the flags of a configuration change event, false if the event does not contain them
+func changedFlags(event *schemaV1.EventStreamResponse) (map[string]interface{}, bool) {
+	if event.Data == nil {
+		return nil, false
+	}
+
+	flags, ok := event.Data.AsMap()["flags"].(map[string]interface{})
+	return flags, ok
+}
+
+// prefetch warms the cache with a bulk evaluation of all flags, if enabled. Evaluations which may not be cached are
+// skipped. Prefetching runs on the event stream and delays the event it precedes, hence it is bounded by a timeout.
+// Failures are logged, the flags are then evaluated on demand.
+func (s *Service) prefetch(ctx context.Context) {
+	if !s.cfg.Prefetch || !s.cache.IsEnabled() {
+		return
+	}
+
+	// whether evaluations can be cached only depends on the prefetch context
+	if _, cacheable := s.cache.Key("", s.cfg.PrefetchContext); !cacheable {
+		s.logger.V(logger.Warn).Info("failed to prefetch flags: the prefetch context can not be cached")
+		return
+	}
+
+	timeout := s.cfg.RequestTimeout
+	if timeout <= 0 {
+		timeout = defaultPrefetchTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	details, err := s.ResolveAll(ctx, s.cfg.PrefetchContext)
+	if err != nil {
+		s.logger.V(logger.Warn).Info(fmt.Sprintf("failed to prefetch flags: %v", err))
+		return
+	}
+
+	for flagKey, detail := range details {
+		if !s.cache.ShouldCache(string(detail.Reason)) {
+			continue
+		}
+
+		cacheKey, _ := s.cache.Key(flagKey, s.cfg.PrefetchContext)
+		s.cache.GetCache().Add(cacheKey, typedDetail(detail))
+	}
+}
+
+// typedDetail converts a bulk evaluation to the resolution detail cached by the evaluation of its type.
+// Bulk evaluations do not distinguish integers, numbers are only cached for float evaluations.
+func typedDetail(detail of.InterfaceResolutionDetail) interface{} {
+	switch value := detail.Value.(type) {
+	case bool:
+		return of.BoolResolutionDetail{Value: value, ProviderResolutionDetail: detail.ProviderResolutionDetail}
+	case string:
+		return of.StringResolutionDetail{Value: value, ProviderResolutionDetail: detail.ProviderResolutionDetail}
+	case float64:
+		return of.FloatResolutionDetail{Value: value, ProviderResolutionDetail: detail.ProviderResolutionDetail}
+	default:
+		return detail
+	}
+}
+
+func (s *Service) purgeCache() {
+	if s.cache.IsEnabled() {
+		s.cache.GetCache().Purge()
+	}
+}
+
 func (s *Service) sendEvent(ctx context.Context, event of.Event) {
 	select {
 	case <-ctx.Done():
@@ -643,6 +888,13 @@ func newClient(cfg Configuration) (schemaConnectV1.ServiceClient, error) {
 			}
 			tlsConfig.RootCAs = caCertPool
 		}
+		if cfg.ClientCertificatePath != "" {
+			clientCertificate, err := certificate.NewClientCertificate(cfg.ClientCertificatePath, cfg.ClientKeyPath)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.GetClientCertificate = clientCertificate.GetClientCertificate
+		}
 	}
 
 	// build options
@@ -657,6 +909,14 @@ func newClient(cfg Configuration) (schemaConnectV1.ServiceClient, error) {
 		options = append(options, connect.WithInterceptors(interceptor))
 	}
 
+	if cfg.HeaderProvider != nil {
+		// headers carry credentials, they are only sent over plaintext TCP connections if explicitly allowed
+		if !cfg.TLSEnabled && cfg.SocketPath == "" && !cfg.InsecureHeaders {
+			return nil, errors.New("the header provider requires TLS, unless insecure headers are allowed")
+		}
+		options = append(options, connect.WithInterceptors(headerInterceptor{provider: cfg.HeaderProvider}))
+	}
+
 	return schemaConnectV1.NewServiceClient(
 		&http.Client{
 			Transport: &http.Transport{
@@ -668,3 +928,32 @@ func newClient(cfg Configuration) (schemaConnectV1.ServiceClient, error) {
 		options...,
 	), nil
 }
+
+// headerInterceptor adds the headers of the provider to every call, the provider is invoked per call so that
+// short-lived tokens are refreshed
+type headerInterceptor struct {
+	provider func(ctx context.Context) map[string]string
+}
+
+func (h headerInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		for key, value := range h.provider(ctx) {
+			req.Header().Set(key, value)
+		}
+		return next(ctx, req)
+	}
+}
+
+func (h headerInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		for key, value := range h.provider(ctx) {
+			conn.RequestHeader().Set(key, value)
+		}
+		return conn
+	}
+}
+
+func (h headerInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
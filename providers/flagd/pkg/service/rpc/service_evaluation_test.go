@@ -2,12 +2,15 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	schemaConnectV1 "buf.build/gen/go/open-feature/flagd/connectrpc/go/flagd/evaluation/v1/evaluationv1connect"
 	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/evaluation/v1"
+	"connectrpc.com/connect"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -56,7 +59,7 @@ func TestBooleanEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -81,7 +84,7 @@ func TestBooleanEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.BoolResolutionDetail{
 					Value: true,
@@ -111,7 +114,7 @@ func TestBooleanEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -136,7 +139,7 @@ func TestBooleanEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -156,7 +159,7 @@ func TestBooleanEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not initialised",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -192,7 +195,7 @@ func TestStringEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -217,7 +220,7 @@ func TestStringEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.StringResolutionDetail{
 					Value: "valid",
@@ -247,7 +250,7 @@ func TestStringEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -272,7 +275,7 @@ func TestStringEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -292,7 +295,7 @@ func TestStringEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not initialised",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -328,7 +331,7 @@ func TestFloatEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -353,7 +356,7 @@ func TestFloatEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.FloatResolutionDetail{
 					Value: 1.005,
@@ -383,7 +386,7 @@ func TestFloatEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -408,7 +411,7 @@ func TestFloatEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -428,7 +431,7 @@ func TestFloatEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not initialised",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -464,7 +467,7 @@ func TestIntEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -489,7 +492,7 @@ func TestIntEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.IntResolutionDetail{
 					Value: 2,
@@ -519,7 +522,7 @@ func TestIntEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -544,7 +547,7 @@ func TestIntEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -564,7 +567,7 @@ func TestIntEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not initialised",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -613,7 +616,7 @@ func TestObjectEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -638,7 +641,7 @@ func TestObjectEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.InterfaceResolutionDetail{
 					Value: expectedValue,
@@ -668,7 +671,7 @@ func TestObjectEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -693,7 +696,7 @@ func TestObjectEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -713,7 +716,7 @@ func TestObjectEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not ready",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -799,3 +802,341 @@ func TestService_isInitialised(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveAll(t *testing.T) {
+	object, err := structpb.NewStruct(map[string]interface{}{"color": "blue"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := Service{
+		cache: cache.NewCacheService(cache.DisabledValue, 10, 0, log),
+		client: &MockClient{
+			allResponse: v1.ResolveAllResponse{
+				Flags: map[string]*v1.AnyFlag{
+					"bool": {
+						Reason:   string(of.StaticReason),
+						Variant:  "on",
+						Value:    &v1.AnyFlag_BoolValue{BoolValue: true},
+						Metadata: metadataStruct,
+					},
+					"string": {
+						Reason:  string(of.TargetingMatchReason),
+						Variant: "greeting",
+						Value:   &v1.AnyFlag_StringValue{StringValue: "hello"},
+					},
+					"number": {
+						Reason:  string(of.StaticReason),
+						Variant: "one",
+						Value:   &v1.AnyFlag_DoubleValue{DoubleValue: 1},
+					},
+					"object": {
+						Reason:  string(of.StaticReason),
+						Variant: "blue",
+						Value:   &v1.AnyFlag_ObjectValue{ObjectValue: object},
+					},
+				},
+			},
+		},
+	}
+
+	details, err := service.ResolveAll(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]of.InterfaceResolutionDetail{
+		"bool": {
+			Value: true,
+			ProviderResolutionDetail: of.ProviderResolutionDetail{
+				Reason: of.StaticReason, Variant: "on", FlagMetadata: metadata,
+			},
+		},
+		"string": {
+			Value: "hello",
+			ProviderResolutionDetail: of.ProviderResolutionDetail{
+				Reason: of.TargetingMatchReason, Variant: "greeting", FlagMetadata: of.FlagMetadata{},
+			},
+		},
+		"number": {
+			Value: float64(1),
+			ProviderResolutionDetail: of.ProviderResolutionDetail{
+				Reason: of.StaticReason, Variant: "one", FlagMetadata: of.FlagMetadata{},
+			},
+		},
+		"object": {
+			Value: map[string]interface{}{"color": "blue"},
+			ProviderResolutionDetail: of.ProviderResolutionDetail{
+				Reason: of.StaticReason, Variant: "blue", FlagMetadata: of.FlagMetadata{},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expected, details, cmpopts.IgnoreFields(of.ProviderResolutionDetail{}, "ResolutionError")); diff != "" {
+		t.Errorf("unexpected bulk evaluation (-want +got):\n%s", diff)
+	}
+}
+
+func TestResolveAllNotInitialised(t *testing.T) {
+	service := Service{cache: cache.NewCacheService(cache.DisabledValue, 10, 0, log)}
+
+	if _, err := service.ResolveAll(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("expected an error for an uninitialised client")
+	}
+}
+
+func TestContextualCaching(t *testing.T) {
+	service := &Service{
+		cache:  cache.NewCacheService(cache.ContextualValue, 10, 0, log),
+		events: make(chan of.Event, 1),
+		logger: log,
+		client: &MockClient{
+			booleanResponse: v1.ResolveBooleanResponse{
+				Value:    true,
+				Reason:   string(of.TargetingMatchReason),
+				Variant:  "on",
+				Metadata: metadataStruct,
+			},
+		},
+	}
+
+	userA := map[string]interface{}{"targetingKey": "a", "email": "a@example.com"}
+	userB := map[string]interface{}{"targetingKey": "b", "email": "b@example.com"}
+
+	detail := service.ResolveBoolean(context.Background(), flagKey, false, userA)
+	if detail.Reason != of.TargetingMatchReason {
+		t.Fatalf("expected first evaluation to be resolved remotely, got reason %s", detail.Reason)
+	}
+
+	detail = service.ResolveBoolean(context.Background(), flagKey, false, map[string]interface{}{
+		"email": "a@example.com", "targetingKey": "a",
+	})
+	if detail.Reason != of.CachedReason {
+		t.Errorf("expected evaluation with equal context to be cached, got reason %s", detail.Reason)
+	}
+
+	detail = service.ResolveBoolean(context.Background(), flagKey, false, userB)
+	if detail.Reason != of.TargetingMatchReason {
+		t.Errorf("expected evaluation with a different context not to be cached, got reason %s", detail.Reason)
+	}
+
+	data, err := structpb.NewStruct(map[string]interface{}{
+		"flags": map[string]interface{}{flagKey: ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.handleConfigurationChangeEvent(context.Background(), &v1.EventStreamResponse{Data: data})
+
+	detail = service.ResolveBoolean(context.Background(), flagKey, false, userA)
+	if detail.Reason != of.TargetingMatchReason {
+		t.Errorf("expected configuration change to invalidate cached evaluations, got reason %s", detail.Reason)
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	client := &MockClient{
+		allResponse: v1.ResolveAllResponse{
+			Flags: map[string]*v1.AnyFlag{
+				"bool": {
+					Reason:  string(of.StaticReason),
+					Variant: "on",
+					Value:   &v1.AnyFlag_BoolValue{BoolValue: true},
+				},
+				"number": {
+					Reason:  string(of.StaticReason),
+					Variant: "two",
+					Value:   &v1.AnyFlag_DoubleValue{DoubleValue: 2},
+				},
+				"targeted": {
+					Reason:  string(of.TargetingMatchReason),
+					Variant: "greeting",
+					Value:   &v1.AnyFlag_StringValue{StringValue: "hello"},
+				},
+			},
+		},
+		stringResponse: v1.ResolveStringResponse{
+			Value:   "hello",
+			Reason:  string(of.TargetingMatchReason),
+			Variant: "greeting",
+		},
+	}
+
+	service := &Service{
+		cache:  cache.NewCacheService(cache.LRUValue, 10, 0, log),
+		cfg:    Configuration{Prefetch: true},
+		events: make(chan of.Event, 2),
+		logger: log,
+		client: client,
+	}
+
+	// when
+	service.handleReadyEvent(context.Background())
+
+	// then
+	boolDetail := service.ResolveBoolean(context.Background(), "bool", false, map[string]interface{}{})
+	if !boolDetail.Value || boolDetail.Reason != ReasonCached {
+		t.Errorf("expected prefetched boolean flag, got %v with reason %s", boolDetail.Value, boolDetail.Reason)
+	}
+
+	floatDetail := service.ResolveFloat(context.Background(), "number", 0, map[string]interface{}{})
+	if floatDetail.Value != 2 || floatDetail.Reason != ReasonCached {
+		t.Errorf("expected prefetched float flag, got %v with reason %s", floatDetail.Value, floatDetail.Reason)
+	}
+
+	// bulk evaluations do not distinguish integers, numbers are not prefetched for int evaluations
+	intDetail := service.ResolveInt(context.Background(), "number", 0, map[string]interface{}{})
+	if intDetail.Reason == ReasonCached {
+		t.Errorf("expected int flag to be resolved remotely, got reason %s", intDetail.Reason)
+	}
+
+	// targeting results depend on the evaluation context, they are not cached
+	stringDetail := service.ResolveString(context.Background(), "targeted", "", map[string]interface{}{})
+	if stringDetail.Reason != of.TargetingMatchReason {
+		t.Errorf("expected targeted flag to be resolved remotely, got reason %s", stringDetail.Reason)
+	}
+
+	// changed flags are prefetched again
+	client.allResponse.Flags["bool"].Value = &v1.AnyFlag_BoolValue{BoolValue: false}
+	data, err := structpb.NewStruct(map[string]interface{}{
+		"flags": map[string]interface{}{"bool": ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.handleConfigurationChangeEvent(context.Background(), &v1.EventStreamResponse{Data: data})
+
+	boolDetail = service.ResolveBoolean(context.Background(), "bool", true, map[string]interface{}{})
+	if boolDetail.Value || boolDetail.Reason != ReasonCached {
+		t.Errorf("expected prefetched changed flag, got %v with reason %s", boolDetail.Value, boolDetail.Reason)
+	}
+}
+
+// hangingClient is a client of an unresponsive flagd, its evaluations block until their context is done
+type hangingClient struct {
+	MockClient
+}
+
+func (h *hangingClient) ResolveBoolean(ctx context.Context, _ *connect.Request[v1.ResolveBooleanRequest]) (*connect.Response[v1.ResolveBooleanResponse], error) {
+	<-ctx.Done()
+	return nil, connect.NewError(connect.CodeDeadlineExceeded, ctx.Err())
+}
+
+func (h *hangingClient) ResolveAll(ctx context.Context, _ *connect.Request[v1.ResolveAllRequest]) (*connect.Response[v1.ResolveAllResponse], error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestPrefetchTimeout(t *testing.T) {
+	service := &Service{
+		cache:  cache.NewCacheService(cache.LRUValue, 10, 0, log),
+		cfg:    Configuration{Prefetch: true, RequestTimeout: 10 * time.Millisecond},
+		events: make(chan of.Event, 1),
+		logger: log,
+		client: &hangingClient{},
+	}
+
+	// when
+	done := make(chan struct{})
+	go func() {
+		service.handleReadyEvent(context.Background())
+		close(done)
+	}()
+
+	// then
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected prefetching to be bounded by the request timeout")
+	}
+	if event := <-service.events; event.EventType != of.ProviderReady {
+		t.Errorf("expected %s event after failed prefetching, got %s", of.ProviderReady, event.EventType)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	service := Service{
+		cache:  cache.NewCacheService(cache.DisabledValue, 0, 0, log),
+		logger: log,
+		client: &hangingClient{},
+		cfg:    Configuration{RequestTimeout: 10 * time.Millisecond},
+	}
+
+	t.Run("evaluations are bounded by the request timeout", func(t *testing.T) {
+		detail := service.ResolveBoolean(context.Background(), flagKey, true, map[string]interface{}{})
+		if !detail.Value || detail.Reason != ReasonTimeout {
+			t.Errorf("expected the default value with reason %s, got %v with reason %s", ReasonTimeout, detail.Value, detail.Reason)
+		}
+		if detail.ResolutionDetail().ErrorCode != of.GeneralCode {
+			t.Errorf("expected error code %s, got %s", of.GeneralCode, detail.ResolutionDetail().ErrorCode)
+		}
+		if !errors.Is(detail.ResolutionError, ErrRequestTimeout) {
+			t.Errorf("expected a request timeout error, got %v", detail.Error())
+		}
+	})
+
+	t.Run("bulk evaluations are bounded by the request timeout", func(t *testing.T) {
+		_, err := service.ResolveAll(context.Background(), map[string]interface{}{})
+		if !errors.Is(err, ErrRequestTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected a request timeout error, got %v", err)
+		}
+	})
+
+	t.Run("the deadline of the caller takes precedence", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		detail := service.ResolveBoolean(ctx, flagKey, true, map[string]interface{}{})
+		if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+			t.Errorf("expected the evaluation to wait for the deadline of the caller, returned after %s", elapsed)
+		}
+		if detail.Reason != ReasonTimeout {
+			t.Errorf("expected reason %s, got %s", ReasonTimeout, detail.Reason)
+		}
+		if detail.ResolutionDetail().ErrorCode != of.GeneralCode {
+			t.Errorf("expected error code %s, got %s", of.GeneralCode, detail.ResolutionDetail().ErrorCode)
+		}
+		// the deadline of the caller is not the request timeout
+		if errors.Is(detail.ResolutionError, ErrRequestTimeout) || !errors.Is(detail.ResolutionError, context.DeadlineExceeded) {
+			t.Errorf("expected a deadline exceeded error without request timeout, got %v", detail.Error())
+		}
+	})
+}
+
+func TestHandleErrorTimeout(t *testing.T) {
+	tests := map[string]struct {
+		err            error
+		bounded        bool
+		requestTimeout bool
+	}{
+		"request timeout": {
+			err:            context.DeadlineExceeded,
+			bounded:        true,
+			requestTimeout: true,
+		},
+		"deadline of the caller": {
+			err: context.DeadlineExceeded,
+		},
+		"deadline exceeded by flagd": {
+			err: connect.NewError(connect.CodeDeadlineExceeded, context.DeadlineExceeded),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resolutionErr := handleError(test.err, test.bounded)
+
+			// the SDK has no timeout error code
+			if code := (of.ProviderResolutionDetail{ResolutionError: resolutionErr}).ResolutionDetail().ErrorCode; code != of.GeneralCode {
+				t.Errorf("expected error code %s, got %s", of.GeneralCode, code)
+			}
+			if reason := errorReason(resolutionErr); reason != ReasonTimeout {
+				t.Errorf("expected reason %s, got %s", ReasonTimeout, reason)
+			}
+			if errors.Is(resolutionErr, ErrRequestTimeout) != test.requestTimeout {
+				t.Errorf("expected request timeout %t, got %v", test.requestTimeout, resolutionErr)
+			}
+		})
+	}
+}
@@ -24,13 +24,16 @@ func TestRetries(t *testing.T) {
 			maxRetries:     1,
 		},
 		client: &client,
-		cache:  cache.NewCacheService(cache.DisabledValue, 0, log),
+		cache:  cache.NewCacheService(cache.DisabledValue, 0, 0, log),
 		events: make(chan of.Event),
 	}
 
 	// when - start event stream, knowing it will result in error
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	go func() {
-		service.startEventStream(context.Background())
+		service.startEventStream(ctx)
 	}()
 
 	// then - expect an error event after retries
@@ -64,7 +67,7 @@ func TestConfigChange(t *testing.T) {
 	t.Run("no cache - do nothing", func(t *testing.T) {
 		// given
 		service := Service{
-			cache:  cache.NewCacheService(cache.DisabledValue, 0, log),
+			cache:  cache.NewCacheService(cache.DisabledValue, 0, 0, log),
 			events: make(chan of.Event),
 		}
 
@@ -89,7 +92,7 @@ func TestConfigChange(t *testing.T) {
 	t.Run("with cache - validate config change event", func(t *testing.T) {
 		// given
 		service := Service{
-			cache:  cache.NewCacheService(cache.InMemValue, 1, log),
+			cache:  cache.NewCacheService(cache.InMemValue, 1, 0, log),
 			events: make(chan of.Event),
 		}
 
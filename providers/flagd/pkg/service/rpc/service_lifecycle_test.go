@@ -10,6 +10,8 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -37,7 +39,7 @@ func TestRPCServiceShutdownCleansUpGoroutines(t *testing.T) {
 	})
 
 	var log logr.Logger
-	cache := cache.NewCacheService(cache.LRUValue, 10, log)
+	cache := cache.NewCacheService(cache.LRUValue, 10, 0, log)
 	srv, cfg := runTestServer(t)
 	srv.eventStreamResponses <- &evaluation.EventStreamResponse{
 		Type: string(flagdService.ProviderReady),
@@ -65,7 +67,7 @@ func TestRPCServiceShutdownDuringEventHandlingCleansUpGoroutines(t *testing.T) {
 	checkGoroutineLeaks(t)
 
 	var log logr.Logger
-	cache := cache.NewCacheService(cache.LRUValue, 10, log)
+	cache := cache.NewCacheService(cache.LRUValue, 10, 0, log)
 	// Run the server. Then, queue up several events so that the service's event
 	// streaming goroutine is forced to block while it waits for consumers to
 	// handle events. When we shut down the service, it should be able to unblock
@@ -122,7 +124,7 @@ func TestRPCServiceShutdownDuringInitRetry(t *testing.T) {
 	// checkGoroutineLeaks(t)
 
 	var log logr.Logger
-	cache := cache.NewCacheService(cache.LRUValue, 10, log)
+	cache := cache.NewCacheService(cache.LRUValue, 10, 0, log)
 	// Run the server. Then, queue up several events so that the service's event
 	// streaming goroutine is forced to block while it waits for consumers to
 	// handle events. When we shut down the service, it should be able to unblock
@@ -152,7 +154,7 @@ func TestRPCServiceShutdownCancelsEventStreamGoroutine(t *testing.T) {
 	checkGoroutineLeaks(t)
 
 	var log logr.Logger
-	cache := cache.NewCacheService(cache.LRUValue, 10, log)
+	cache := cache.NewCacheService(cache.LRUValue, 10, 0, log)
 	// Run the server. Then, queue up several events so that the service's event
 	// streaming goroutine is forced to block while it waits for consumers to
 	// handle events. When we shut down the service, it should be able to unblock
@@ -202,6 +204,110 @@ func TestRPCServiceShutdownCancelsEventStreamGoroutine(t *testing.T) {
 	service.Shutdown()
 }
 
+func TestRPCServiceReconnectsAfterRetriesExhausted(t *testing.T) {
+	var log logr.Logger
+	cache := cache.NewCacheService(cache.LRUValue, 10, 0, log)
+	srv, cfg := runTestServer(t)
+	srv.eventStreamResponses <- &evaluation.EventStreamResponse{
+		Type: string(flagdService.ProviderReady),
+	}
+	cfg.RetryBackoff = 10 * time.Millisecond
+	cfg.RetryGracePeriod = 60
+
+	service := NewService(cfg, cache, log, 1 /*=retries*/)
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(service.Shutdown)
+
+	expectEvent(t, service, of.ProviderReady, "")
+
+	// break the stream, then fail the single reconnection attempt
+	srv.eventStreamErrors <- errors.New("server error")
+	expectEvent(t, service, of.ProviderStale, "stream error")
+	srv.eventStreamErrors <- errors.New("server error")
+	expectEvent(t, service, of.ProviderError, "grpc connection establishment failed")
+
+	// retrying continues in the background and recovers once flagd is back
+	srv.eventStreamResponses <- &evaluation.EventStreamResponse{
+		Type: string(flagdService.ProviderReady),
+	}
+	expectEvent(t, service, of.ProviderReady, "")
+}
+
+func TestRPCServiceStaleGracePeriod(t *testing.T) {
+	var log logr.Logger
+	cache := cache.NewCacheService(cache.LRUValue, 10, 0, log)
+	srv, cfg := runTestServer(t)
+	srv.eventStreamResponses <- &evaluation.EventStreamResponse{
+		Type: string(flagdService.ProviderReady),
+	}
+	cfg.RetryBackoff = 10 * time.Millisecond
+	cfg.RetryGracePeriod = 0
+
+	service := NewService(cfg, cache, log, 5 /*=retries*/)
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(service.Shutdown)
+
+	expectEvent(t, service, of.ProviderReady, "")
+	cache.GetCache().Add("flag", "value")
+
+	// without a grace period, the stale state escalates to an error right away
+	srv.eventStreamErrors <- errors.New("server error")
+	expectEvent(t, service, of.ProviderStale, "stream error")
+	expectEvent(t, service, of.ProviderError, "grace period")
+	if _, ok := cache.GetCache().Get("flag"); ok {
+		t.Error("expected cache to be purged after the grace period")
+	}
+
+	srv.eventStreamResponses <- &evaluation.EventStreamResponse{
+		Type: string(flagdService.ProviderReady),
+	}
+	expectEvent(t, service, of.ProviderReady, "")
+}
+
+func TestRPCServiceHeaderProvider(t *testing.T) {
+	var log logr.Logger
+	cache := cache.NewCacheService(cache.DisabledValue, 0, 0, log)
+	srv, cfg := runTestServer(t)
+	srv.eventStreamResponses <- &evaluation.EventStreamResponse{
+		Type: string(flagdService.ProviderReady),
+	}
+
+	var calls atomic.Int32
+	cfg.HeaderProvider = func(_ context.Context) map[string]string {
+		return map[string]string{"Authorization": fmt.Sprintf("Bearer token-%d", calls.Add(1))}
+	}
+	cfg.InsecureHeaders = true
+
+	service := NewService(cfg, cache, log, 3 /*=retries*/)
+	if err := service.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(service.Shutdown)
+
+	select {
+	case event := <-service.EventChannel():
+		if event.EventType != of.ProviderReady {
+			t.Fatalf("expected %s event, got %s", of.ProviderReady, event.EventType)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the provider to be ready")
+	}
+
+	if header := srv.header("EventStream"); header.Get("Authorization") != "Bearer token-1" {
+		t.Errorf("expected the event stream to be authorized with the first token, got %q", header.Get("Authorization"))
+	}
+
+	// the header provider is invoked for every call
+	service.ResolveBoolean(context.Background(), "flag", false, map[string]interface{}{})
+	if header := srv.header("ResolveBoolean"); header.Get("Authorization") != "Bearer token-2" {
+		t.Errorf("expected the evaluation to be authorized with a refreshed token, got %q", header.Get("Authorization"))
+	}
+}
+
 // At the end of the test, if no other failures have occurred, check for
 // goroutine leaks, and fail the test if any were found.
 func checkGoroutineLeaks(t *testing.T) {
@@ -223,9 +329,26 @@ type testServer struct {
 	evaluationv1connect.UnimplementedServiceHandler
 	eventStreamErrors    chan error
 	eventStreamResponses chan *evaluation.EventStreamResponse
+
+	// headers records the request headers of the last call, keyed by the procedure name
+	headers sync.Map
+}
+
+func (f *testServer) header(procedure string) http.Header {
+	header, ok := f.headers.Load(procedure)
+	if !ok {
+		return http.Header{}
+	}
+	return header.(http.Header)
+}
+
+func (f *testServer) ResolveBoolean(_ context.Context, req *connect.Request[evaluation.ResolveBooleanRequest]) (*connect.Response[evaluation.ResolveBooleanResponse], error) {
+	f.headers.Store("ResolveBoolean", req.Header().Clone())
+	return connect.NewResponse(&evaluation.ResolveBooleanResponse{Value: true, Reason: string(of.StaticReason)}), nil
 }
 
 func (f *testServer) EventStream(ctx context.Context, req *connect.Request[evaluation.EventStreamRequest], stream *connect.ServerStream[evaluation.EventStreamResponse]) error {
+	f.headers.Store("EventStream", req.Header().Clone())
 	for {
 		select {
 		case rsp := <-f.eventStreamResponses:
@@ -265,3 +388,17 @@ func runTestServer(t *testing.T) (*testServer, Configuration) {
 	cfg := Configuration{Host: host, Port: uint16(port)}
 	return ts, cfg
 }
+
+// expectEvent waits for the next event of the service and checks its type and message
+func expectEvent(t *testing.T, service *Service, eventType of.EventType, message string) {
+	t.Helper()
+	select {
+	case event := <-service.EventChannel():
+		if event.EventType != eventType || !strings.Contains(event.Message, message) {
+			t.Fatalf("expected %s event with message %q, got %s with message %q",
+				eventType, message, event.EventType, event.Message)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for %s event", eventType)
+	}
+}
@@ -0,0 +1,207 @@
+package multiprovider
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+// MetadataProviderName is the event metadata key of the name of the provider an event originates from
+const MetadataProviderName = "multiprovider-provider-name"
+
+// statePrecedence ranks the provider states as defined by the multi-provider specification, the aggregate status is
+// the state of highest precedence among all providers
+var statePrecedence = map[of.State]int{
+	of.ReadyState:    0,
+	of.StaleState:    1,
+	of.ErrorState:    2,
+	of.NotReadyState: 3,
+	of.FatalState:    4,
+}
+
+// namedEvent is an event of the provider of the given name
+type namedEvent struct {
+	of.Event
+	providerName string
+}
+
+// startEventForwarding subscribes to the events of all providers implementing of.EventHandler until ctx is done.
+// Provider events are drained even if event publishing is disabled, so providers never block on emitting events.
+func (mp *MultiProvider) startEventForwarding(ctx context.Context) {
+	pipe := make(chan namedEvent)
+	var listeners sync.WaitGroup
+	for _, p := range mp.ordered {
+		handler, ok := p.Provider.(of.EventHandler)
+		if !ok {
+			continue
+		}
+
+		listeners.Add(1)
+		go func(name string, events <-chan of.Event) {
+			defer listeners.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					select {
+					case pipe <- namedEvent{Event: event, providerName: name}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}(p.Name, handler.EventChannel())
+	}
+
+	mp.workers.Add(1)
+	go func() {
+		defer mp.workers.Done()
+		// events of all providers are handled sequentially, so status changes are emitted in order
+		for {
+			select {
+			case <-ctx.Done():
+				listeners.Wait()
+				return
+			case event := <-pipe:
+				mp.handleEvent(ctx, event)
+			}
+		}
+	}()
+}
+
+// handleEvent updates the state of the provider the event originates from. Configuration changes are re-emitted,
+// state changes only if they change the aggregate status.
+func (mp *MultiProvider) handleEvent(ctx context.Context, event namedEvent) {
+	metadata := make(map[string]any, len(event.EventMetadata)+1)
+	for key, value := range event.EventMetadata {
+		metadata[key] = value
+	}
+	metadata[MetadataProviderName] = event.providerName
+	event.EventMetadata = metadata
+
+	if event.EventType == of.ProviderConfigChange {
+		mp.logger.LogAttrs(ctx, slog.LevelDebug, "provider configuration changed",
+			slog.String(MetadataProviderName, event.providerName), slog.Any("flags", event.FlagChanges))
+		event.ProviderName = event.providerName
+		mp.emit(ctx, event.Event)
+		return
+	}
+
+	state, ok := eventState(event.Event)
+	if !ok {
+		return
+	}
+	mp.logger.LogAttrs(ctx, slog.LevelDebug, "provider state changed",
+		slog.String(MetadataProviderName, event.providerName), slog.String("state", string(state)),
+		slog.String("event-message", event.Message))
+
+	status, changed, initializing := mp.updateProviderState(event.providerName, state)
+	if !changed || initializing {
+		// the outcome of the initialization is reported by Init
+		return
+	}
+
+	statusEvent, ok := statusEvent(status, event.ProviderEventDetails)
+	if !ok {
+		return
+	}
+	statusEvent.ProviderName = mp.metadata.Name
+	mp.emit(ctx, statusEvent)
+}
+
+// updateProviderState sets the state of a provider and returns the resulting aggregate status, whether the aggregate
+// status changed and whether the multi-provider is initializing
+func (mp *MultiProvider) updateProviderState(name string, state of.State) (of.State, bool, bool) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	mp.providerStates[name] = state
+	status := aggregateState(mp.providerStates)
+	changed := status != mp.status
+	mp.status = status
+	return status, changed, mp.initializing
+}
+
+// emit publishes an event, unless event publishing is disabled
+func (mp *MultiProvider) emit(ctx context.Context, event of.Event) {
+	if mp.events == nil {
+		return
+	}
+
+	select {
+	case mp.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// aggregateState returns the state of highest precedence among the states of the providers
+func aggregateState(states map[string]of.State) of.State {
+	aggregate := of.ReadyState
+	for _, state := range states {
+		if statePrecedence[state] > statePrecedence[aggregate] {
+			aggregate = state
+		}
+	}
+	return aggregate
+}
+
+// eventState returns the provider state an event transitions to
+func eventState(event of.Event) (of.State, bool) {
+	switch event.EventType {
+	case of.ProviderReady:
+		return of.ReadyState, true
+	case of.ProviderStale:
+		return of.StaleState, true
+	case of.ProviderError:
+		if event.ErrorCode == of.ProviderFatalCode {
+			return of.FatalState, true
+		}
+		return of.ErrorState, true
+	default:
+		return "", false
+	}
+}
+
+// initState returns the provider state resulting from the outcome of its initialization
+func initState(err error) of.State {
+	if err == nil {
+		return of.ReadyState
+	}
+
+	var initErr *of.ProviderInitError
+	if errors.As(err, &initErr) && initErr.ErrorCode == of.ProviderFatalCode {
+		return of.FatalState
+	}
+	return of.ErrorState
+}
+
+// statusEvent returns the event reporting an aggregate status. There is no event for the NOT_READY status.
+func statusEvent(status of.State, details of.ProviderEventDetails) (of.Event, bool) {
+	event := of.Event{ProviderEventDetails: details}
+	switch status {
+	case of.ReadyState:
+		event.EventType = of.ProviderReady
+		event.ErrorCode = ""
+	case of.StaleState:
+		event.EventType = of.ProviderStale
+		event.ErrorCode = ""
+	case of.ErrorState:
+		event.EventType = of.ProviderError
+		if event.ErrorCode == of.ProviderFatalCode {
+			event.ErrorCode = of.GeneralCode
+		}
+	case of.FatalState:
+		event.EventType = of.ProviderError
+		event.ErrorCode = of.ProviderFatalCode
+	default:
+		return of.Event{}, false
+	}
+	return event, true
+}
@@ -0,0 +1,179 @@
+package multiprovider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk-contrib/providers/multi-provider/pkg/strategies"
+	of "github.com/open-feature/go-sdk/openfeature"
+	imp "github.com/open-feature/go-sdk/openfeature/memprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventingProvider is an in-memory provider emitting the events sent to it
+type eventingProvider struct {
+	imp.InMemoryProvider
+	events  chan of.Event
+	initErr error
+}
+
+func newEventingProvider() *eventingProvider {
+	return &eventingProvider{
+		InMemoryProvider: imp.NewInMemoryProvider(map[string]imp.InMemoryFlag{}),
+		events:           make(chan of.Event),
+	}
+}
+
+func (p *eventingProvider) EventChannel() <-chan of.Event {
+	return p.events
+}
+
+func (p *eventingProvider) Init(of.EvaluationContext) error {
+	return p.initErr
+}
+
+func (p *eventingProvider) Shutdown() {}
+
+func (p *eventingProvider) emit(t *testing.T, event of.Event) {
+	t.Helper()
+	select {
+	case p.events <- event:
+	case <-time.After(time.Second):
+		t.Fatal("the multi-provider did not receive the event")
+	}
+}
+
+func expectEvent(t *testing.T, mp *MultiProvider, eventType of.EventType) of.Event {
+	t.Helper()
+	select {
+	case event := <-mp.EventChannel():
+		require.Equal(t, eventType, event.EventType)
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s event", eventType)
+		return of.Event{}
+	}
+}
+
+// awaitProviderState waits until the multi-provider tracked the state of a provider
+func awaitProviderState(t *testing.T, mp *MultiProvider, name string, state of.State) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		mp.mu.RLock()
+		defer mp.mu.RUnlock()
+		return mp.providerStates[name] == state
+	}, time.Second, time.Millisecond)
+}
+
+func TestAggregateState(t *testing.T) {
+	tests := map[string]struct {
+		states []of.State
+		expect of.State
+	}{
+		"ready":                    {states: []of.State{of.ReadyState, of.ReadyState}, expect: of.ReadyState},
+		"stale over ready":         {states: []of.State{of.ReadyState, of.StaleState}, expect: of.StaleState},
+		"error over stale":         {states: []of.State{of.ErrorState, of.StaleState}, expect: of.ErrorState},
+		"not ready over error":     {states: []of.State{of.ErrorState, of.NotReadyState}, expect: of.NotReadyState},
+		"fatal over not ready":     {states: []of.State{of.NotReadyState, of.FatalState, of.ReadyState}, expect: of.FatalState},
+		"no providers are ready":   {states: nil, expect: of.ReadyState},
+		"single provider in error": {states: []of.State{of.ErrorState}, expect: of.ErrorState},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			states := make(map[string]of.State, len(test.states))
+			for i, state := range test.states {
+				states[string(rune('a'+i))] = state
+			}
+			assert.Equal(t, test.expect, aggregateState(states))
+		})
+	}
+}
+
+func TestMultiProvider_Events(t *testing.T) {
+	// given
+	local := newEventingProvider()
+	remote := newEventingProvider()
+	mp, err := NewMultiProviderOrdered([]*strategies.NamedProvider{
+		{Name: "local", Provider: local},
+		{Name: "remote", Provider: remote},
+	}, StrategyFirstMatch, WithEventPublishing())
+	require.NoError(t, err)
+
+	require.NoError(t, mp.Init(of.EvaluationContext{}))
+	t.Cleanup(mp.Shutdown)
+	assert.Equal(t, of.ReadyState, mp.Status())
+
+	// configuration changes are re-emitted with the originating provider
+	remote.emit(t, of.Event{EventType: of.ProviderConfigChange, ProviderEventDetails: of.ProviderEventDetails{
+		FlagChanges: []string{"flag"},
+	}})
+	event := expectEvent(t, mp, of.ProviderConfigChange)
+	assert.Equal(t, "remote", event.ProviderName)
+	assert.Equal(t, "remote", event.EventMetadata[MetadataProviderName])
+	assert.Equal(t, []string{"flag"}, event.FlagChanges)
+
+	// state changes changing the aggregate status are emitted
+	local.emit(t, of.Event{EventType: of.ProviderStale})
+	event = expectEvent(t, mp, of.ProviderStale)
+	assert.Equal(t, "local", event.EventMetadata[MetadataProviderName])
+	assert.Equal(t, of.StaleState, mp.Status())
+
+	remote.emit(t, of.Event{EventType: of.ProviderError, ProviderEventDetails: of.ProviderEventDetails{Message: "lost"}})
+	event = expectEvent(t, mp, of.ProviderError)
+	assert.Equal(t, "lost", event.Message)
+	assert.Equal(t, of.ErrorState, mp.Status())
+
+	// the recovery of the stale provider does not change the aggregate status, no event is emitted for it
+	local.emit(t, of.Event{EventType: of.ProviderReady})
+	awaitProviderState(t, mp, "local", of.ReadyState)
+	remote.emit(t, of.Event{EventType: of.ProviderReady})
+	event = expectEvent(t, mp, of.ProviderReady)
+	assert.Equal(t, "remote", event.EventMetadata[MetadataProviderName])
+	assert.Equal(t, of.ReadyState, mp.Status())
+
+	// fatal errors take precedence
+	remote.emit(t, of.Event{EventType: of.ProviderError, ProviderEventDetails: of.ProviderEventDetails{
+		ErrorCode: of.ProviderFatalCode,
+	}})
+	event = expectEvent(t, mp, of.ProviderError)
+	assert.Equal(t, of.ProviderFatalCode, event.ErrorCode)
+	assert.Equal(t, of.FatalState, mp.Status())
+
+	local.emit(t, of.Event{EventType: of.ProviderError})
+	awaitProviderState(t, mp, "local", of.ErrorState)
+	assert.Equal(t, of.FatalState, mp.Status())
+}
+
+func TestMultiProvider_EventsWithoutPublishing(t *testing.T) {
+	provider := newEventingProvider()
+	mp, err := NewMultiProvider(ProviderMap{"provider": provider}, StrategyFirstMatch)
+	require.NoError(t, err)
+
+	require.NoError(t, mp.Init(of.EvaluationContext{}))
+	t.Cleanup(mp.Shutdown)
+
+	// the events of the providers are drained and tracked
+	provider.emit(t, of.Event{EventType: of.ProviderConfigChange})
+	provider.emit(t, of.Event{EventType: of.ProviderStale})
+	awaitProviderState(t, mp, "provider", of.StaleState)
+	assert.Equal(t, of.StaleState, mp.Status())
+}
+
+func TestMultiProvider_InitFatal(t *testing.T) {
+	fatal := newEventingProvider()
+	fatal.initErr = &of.ProviderInitError{ErrorCode: of.ProviderFatalCode, Message: "invalid configuration"}
+	failing := newEventingProvider()
+	failing.initErr = &of.ProviderInitError{ErrorCode: of.GeneralCode, Message: "unavailable"}
+
+	mp, err := NewMultiProvider(ProviderMap{"fatal": fatal, "failing": failing}, StrategyFirstMatch)
+	require.NoError(t, err)
+
+	require.Error(t, mp.Init(of.EvaluationContext{}))
+	t.Cleanup(mp.Shutdown)
+	assert.Equal(t, of.FatalState, mp.Status())
+
+	mp.Shutdown()
+	assert.Equal(t, of.NotReadyState, mp.Status())
+}
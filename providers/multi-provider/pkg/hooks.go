@@ -0,0 +1,196 @@
+package multiprovider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/open-feature/go-sdk-contrib/providers/multi-provider/pkg/strategies"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+// hookedProvider runs hooks around the evaluations of a provider. The strategies call providers directly, so the
+// hooks of a provider would otherwise never run once it is wrapped by the multi-provider.
+type hookedProvider struct {
+	of.FeatureProvider
+	// hooks of the multi-provider, run before the hooks of the provider
+	hooks []of.Hook
+}
+
+var _ of.FeatureProvider = (*hookedProvider)(nil)
+
+// withHooks wraps the providers to run the given hooks followed by their own hooks around their evaluations
+func withHooks(providers []*strategies.NamedProvider, hooks []of.Hook) []*strategies.NamedProvider {
+	wrapped := make([]*strategies.NamedProvider, 0, len(providers))
+	for _, p := range providers {
+		wrapped = append(wrapped, &strategies.NamedProvider{
+			Name:     p.Name,
+			Provider: &hookedProvider{FeatureProvider: p.Provider, hooks: hooks},
+		})
+	}
+	return wrapped
+}
+
+// Hooks returns no hooks, the hooks of the provider are run by the wrapper
+func (h *hookedProvider) Hooks() []of.Hook {
+	return []of.Hook{}
+}
+
+func (h *hookedProvider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx of.FlattenedContext) of.BoolResolutionDetail {
+	value, detail := h.evaluate(ctx, flag, of.Boolean, defaultValue, evalCtx, func(evalCtx of.FlattenedContext) (any, of.ProviderResolutionDetail) {
+		r := h.FeatureProvider.BooleanEvaluation(ctx, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.BoolResolutionDetail{Value: value.(bool), ProviderResolutionDetail: detail}
+}
+
+func (h *hookedProvider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx of.FlattenedContext) of.StringResolutionDetail {
+	value, detail := h.evaluate(ctx, flag, of.String, defaultValue, evalCtx, func(evalCtx of.FlattenedContext) (any, of.ProviderResolutionDetail) {
+		r := h.FeatureProvider.StringEvaluation(ctx, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.StringResolutionDetail{Value: value.(string), ProviderResolutionDetail: detail}
+}
+
+func (h *hookedProvider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx of.FlattenedContext) of.FloatResolutionDetail {
+	value, detail := h.evaluate(ctx, flag, of.Float, defaultValue, evalCtx, func(evalCtx of.FlattenedContext) (any, of.ProviderResolutionDetail) {
+		r := h.FeatureProvider.FloatEvaluation(ctx, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.FloatResolutionDetail{Value: value.(float64), ProviderResolutionDetail: detail}
+}
+
+func (h *hookedProvider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx of.FlattenedContext) of.IntResolutionDetail {
+	value, detail := h.evaluate(ctx, flag, of.Int, defaultValue, evalCtx, func(evalCtx of.FlattenedContext) (any, of.ProviderResolutionDetail) {
+		r := h.FeatureProvider.IntEvaluation(ctx, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.IntResolutionDetail{Value: value.(int64), ProviderResolutionDetail: detail}
+}
+
+func (h *hookedProvider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	value, detail := h.evaluate(ctx, flag, of.Object, defaultValue, evalCtx, func(evalCtx of.FlattenedContext) (any, of.ProviderResolutionDetail) {
+		r := h.FeatureProvider.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// evaluate runs the hooks around the evaluation, following the hook lifecycle of the SDK client: before hooks may
+// extend the evaluation context, a failing before or after hook runs the error hooks and results in the default
+// value, and the finally hooks always run. Like the SDK client, the hooks of the provider are obtained per evaluation.
+func (h *hookedProvider) evaluate(
+	ctx context.Context, flag string, flagType of.Type, defaultValue any, flatCtx of.FlattenedContext,
+	resolve func(of.FlattenedContext) (any, of.ProviderResolutionDetail),
+) (any, of.ProviderResolutionDetail) {
+	hooks := append(append([]of.Hook{}, h.hooks...), h.FeatureProvider.Hooks()...)
+	if len(hooks) == 0 {
+		return resolve(flatCtx)
+	}
+
+	evalCtx := toEvaluationContext(flatCtx)
+	hookCtx := h.hookContext(flag, flagType, defaultValue, evalCtx)
+	hints := of.NewHookHints(nil)
+
+	details := of.InterfaceEvaluationDetails{
+		Value: defaultValue,
+		EvaluationDetails: of.EvaluationDetails{
+			FlagKey:  flag,
+			FlagType: flagType,
+		},
+	}
+	defer func() {
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i].Finally(ctx, hookCtx, details, hints)
+		}
+	}()
+
+	for _, hook := range hooks {
+		result, err := hook.Before(ctx, hookCtx, hints)
+		if err != nil {
+			return defaultValue, hookError(ctx, hooks, hookCtx, hints, &details, fmt.Errorf("before hook: %w", err))
+		}
+		if result != nil {
+			evalCtx = mergeEvaluationContexts(*result, evalCtx)
+			hookCtx = h.hookContext(flag, flagType, defaultValue, evalCtx)
+		}
+	}
+
+	value, detail := resolve(toFlattenedContext(evalCtx))
+	details.Value = value
+	details.ResolutionDetail = detail.ResolutionDetail()
+	if err := detail.Error(); err != nil {
+		errorHooks(ctx, hooks, hookCtx, hints, fmt.Errorf("error code: %w", err))
+		return value, detail
+	}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].After(ctx, hookCtx, details, hints); err != nil {
+			details.Value = defaultValue
+			return defaultValue, hookError(ctx, hooks, hookCtx, hints, &details, fmt.Errorf("after hook: %w", err))
+		}
+	}
+
+	return value, detail
+}
+
+// hookError runs the error hooks for a failing hook and returns the resolution detail of the failed evaluation
+func hookError(
+	ctx context.Context, hooks []of.Hook, hookCtx of.HookContext, hints of.HookHints,
+	details *of.InterfaceEvaluationDetails, err error,
+) of.ProviderResolutionDetail {
+	errorHooks(ctx, hooks, hookCtx, hints, err)
+
+	detail := of.ProviderResolutionDetail{
+		ResolutionError: of.NewGeneralResolutionError(err.Error()),
+		Reason:          of.ErrorReason,
+	}
+	details.ResolutionDetail = detail.ResolutionDetail()
+	return detail
+}
+
+// errorHooks runs the error hooks in reverse order
+func errorHooks(ctx context.Context, hooks []of.Hook, hookCtx of.HookContext, hints of.HookHints, err error) {
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].Error(ctx, hookCtx, err, hints)
+	}
+}
+
+// hookContext returns the hook context of an evaluation. The multi-provider does not know the client the evaluation
+// originates from, so the client metadata is empty.
+func (h *hookedProvider) hookContext(flag string, flagType of.Type, defaultValue any, evalCtx of.EvaluationContext) of.HookContext {
+	return of.NewHookContext(flag, flagType, defaultValue, of.NewClientMetadata(""), h.Metadata(), evalCtx)
+}
+
+// toEvaluationContext converts a flattened evaluation context back to an evaluation context
+func toEvaluationContext(flatCtx of.FlattenedContext) of.EvaluationContext {
+	attributes := make(map[string]any, len(flatCtx))
+	for key, value := range flatCtx {
+		if key != of.TargetingKey {
+			attributes[key] = value
+		}
+	}
+	targetingKey, _ := flatCtx[of.TargetingKey].(string)
+	return of.NewEvaluationContext(targetingKey, attributes)
+}
+
+// toFlattenedContext flattens an evaluation context, as passed to providers
+func toFlattenedContext(evalCtx of.EvaluationContext) of.FlattenedContext {
+	flatCtx := evalCtx.Attributes()
+	if evalCtx.TargetingKey() != "" {
+		flatCtx[of.TargetingKey] = evalCtx.TargetingKey()
+	}
+	return flatCtx
+}
+
+// mergeEvaluationContexts merges evaluation contexts, the first context takes precedence
+func mergeEvaluationContexts(first of.EvaluationContext, second of.EvaluationContext) of.EvaluationContext {
+	attributes := second.Attributes()
+	maps.Copy(attributes, first.Attributes())
+
+	targetingKey := first.TargetingKey()
+	if targetingKey == "" {
+		targetingKey = second.TargetingKey()
+	}
+	return of.NewEvaluationContext(targetingKey, attributes)
+}
@@ -0,0 +1,175 @@
+package multiprovider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/open-feature/go-sdk-contrib/providers/multi-provider/pkg/strategies"
+	of "github.com/open-feature/go-sdk/openfeature"
+	imp "github.com/open-feature/go-sdk/openfeature/memprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the stages of the hooks run
+type recorder struct {
+	mu     sync.Mutex
+	stages []string
+}
+
+func (r *recorder) record(stage string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stages = append(r.stages, stage)
+}
+
+func (r *recorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.stages...)
+}
+
+// recordingHook records its stages, its before stage optionally fails or sets an evaluation context attribute
+type recordingHook struct {
+	of.UnimplementedHook
+	name      string
+	recorder  *recorder
+	attribute string
+	beforeErr error
+	contexts  []of.HookContext
+}
+
+func (h *recordingHook) Before(_ context.Context, hookCtx of.HookContext, _ of.HookHints) (*of.EvaluationContext, error) {
+	h.recorder.record("before " + h.name)
+	h.contexts = append(h.contexts, hookCtx)
+	if h.beforeErr != nil {
+		return nil, h.beforeErr
+	}
+	if h.attribute == "" {
+		return nil, nil
+	}
+	evalCtx := of.NewTargetlessEvaluationContext(map[string]any{h.attribute: true})
+	return &evalCtx, nil
+}
+
+func (h *recordingHook) After(context.Context, of.HookContext, of.InterfaceEvaluationDetails, of.HookHints) error {
+	h.recorder.record("after " + h.name)
+	return nil
+}
+
+func (h *recordingHook) Error(_ context.Context, _ of.HookContext, err error, _ of.HookHints) {
+	h.recorder.record("error " + h.name)
+}
+
+func (h *recordingHook) Finally(context.Context, of.HookContext, of.InterfaceEvaluationDetails, of.HookHints) {
+	h.recorder.record("finally " + h.name)
+}
+
+// hookingProvider is an in-memory provider with hooks
+type hookingProvider struct {
+	imp.InMemoryProvider
+	hooks []of.Hook
+}
+
+func (p *hookingProvider) Hooks() []of.Hook {
+	return p.hooks
+}
+
+func TestMultiProvider_Hooks(t *testing.T) {
+	// the flag is enabled for evaluation contexts with the attribute set by the hook of the provider
+	evaluator := func(_ imp.InMemoryFlag, flatCtx of.FlattenedContext) (any, of.ProviderResolutionDetail) {
+		enabled, _ := flatCtx["enriched"].(bool)
+		return enabled, of.ProviderResolutionDetail{Reason: of.TargetingMatchReason}
+	}
+	flags := map[string]imp.InMemoryFlag{
+		"flag": {State: imp.Enabled, ContextEvaluator: &evaluator},
+	}
+
+	t.Run("hooks run around the evaluation of each provider", func(t *testing.T) {
+		rec := &recorder{}
+		outer := &recordingHook{name: "multi", recorder: rec}
+		inner := &recordingHook{name: "remote", recorder: rec, attribute: "enriched"}
+		local := &hookingProvider{
+			InMemoryProvider: imp.NewInMemoryProvider(map[string]imp.InMemoryFlag{}),
+			hooks:            []of.Hook{&recordingHook{name: "local", recorder: rec}},
+		}
+		remote := &hookingProvider{InMemoryProvider: imp.NewInMemoryProvider(flags), hooks: []of.Hook{inner}}
+
+		mp, err := NewMultiProviderOrdered([]*strategies.NamedProvider{
+			{Name: "local", Provider: local},
+			{Name: "remote", Provider: remote},
+		}, StrategyFirstMatch, WithHooks(outer))
+		require.NoError(t, err)
+
+		detail := mp.BooleanEvaluation(context.Background(), "flag", false, of.FlattenedContext{of.TargetingKey: "user"})
+		require.NoError(t, detail.Error())
+		assert.True(t, detail.Value)
+
+		assert.Equal(t, []string{
+			// the flag is not found by the local provider
+			"before multi", "before local", "error local", "error multi", "finally local", "finally multi",
+			"before multi", "before remote", "after remote", "after multi", "finally remote", "finally multi",
+		}, rec.recorded())
+
+		require.Len(t, inner.contexts, 1)
+		hookCtx := inner.contexts[0]
+		assert.Equal(t, "flag", hookCtx.FlagKey())
+		assert.Equal(t, of.Boolean, hookCtx.FlagType())
+		assert.Equal(t, false, hookCtx.DefaultValue())
+		assert.Equal(t, "InMemoryProvider", hookCtx.ProviderMetadata().Name)
+		assert.Equal(t, "user", hookCtx.EvaluationContext().TargetingKey())
+	})
+
+	t.Run("a failing before hook results in the default value", func(t *testing.T) {
+		rec := &recorder{}
+		provider := &hookingProvider{
+			InMemoryProvider: imp.NewInMemoryProvider(flags),
+			hooks:            []of.Hook{&recordingHook{name: "provider", recorder: rec, beforeErr: errors.New("denied")}},
+		}
+
+		mp, err := NewMultiProvider(ProviderMap{"provider": provider}, StrategyFirstMatch)
+		require.NoError(t, err)
+
+		detail := mp.BooleanEvaluation(context.Background(), "flag", true, of.FlattenedContext{})
+		assert.True(t, detail.Value)
+		assert.Equal(t, of.GeneralCode, detail.ResolutionDetail().ErrorCode)
+		assert.Equal(t, []string{"before provider", "error provider", "finally provider"}, rec.recorded())
+	})
+
+	t.Run("hooks run around the evaluation of the fallback provider", func(t *testing.T) {
+		rec := &recorder{}
+		enabled := imp.NewInMemoryProvider(map[string]imp.InMemoryFlag{
+			"flag": {State: imp.Enabled, DefaultVariant: "on", Variants: map[string]any{"on": true}},
+		})
+		disabled := imp.NewInMemoryProvider(map[string]imp.InMemoryFlag{
+			"flag": {State: imp.Enabled, DefaultVariant: "off", Variants: map[string]any{"off": false}},
+		})
+		fallback := &hookingProvider{
+			InMemoryProvider: imp.NewInMemoryProvider(flags),
+			hooks:            []of.Hook{&recordingHook{name: "fallback", recorder: rec, attribute: "enriched"}},
+		}
+
+		mp, err := NewMultiProviderOrdered([]*strategies.NamedProvider{
+			{Name: "enabled", Provider: enabled},
+			{Name: "disabled", Provider: disabled},
+		}, StrategyComparison, WithFallbackProvider(fallback), WithHooks(&recordingHook{name: "multi", recorder: rec}))
+		require.NoError(t, err)
+
+		// the providers disagree, the fallback provider is enabled by the attribute set by its hook
+		detail := mp.BooleanEvaluation(context.Background(), "flag", false, of.FlattenedContext{})
+		require.NoError(t, detail.Error())
+		assert.True(t, detail.Value)
+		assert.Contains(t, rec.recorded(), "after fallback")
+	})
+
+	t.Run("providers without hooks are evaluated as is", func(t *testing.T) {
+		mp, err := NewMultiProvider(ProviderMap{"provider": imp.NewInMemoryProvider(flags)}, StrategyFirstMatch)
+		require.NoError(t, err)
+
+		detail := mp.BooleanEvaluation(context.Background(), "flag", true, of.FlattenedContext{"enriched": false})
+		require.NoError(t, detail.Error())
+		assert.False(t, detail.Value)
+	})
+}
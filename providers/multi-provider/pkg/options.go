@@ -29,6 +29,23 @@ func WithFallbackProvider(p of.FeatureProvider) Option {
 	}
 }
 
+// WithObjectComparator Sets the comparator of object values for the StrategyComparison. Object values are compared
+// with strategies.JSONEqual by default.
+func WithObjectComparator(c strategies.ObjectComparator) Option {
+	return func(conf *Configuration) {
+		conf.objectComparator = c
+	}
+}
+
+// WithShadowOptions Configures the StrategyShadow, e.g. the handler of mismatches. The timeout of shadow evaluations
+// defaults to the timeout set with WithTimeout, and object values are compared with the comparator set with
+// WithObjectComparator.
+func WithShadowOptions(options ...strategies.ShadowOption) Option {
+	return func(conf *Configuration) {
+		conf.shadowOptions = append(conf.shadowOptions, options...)
+	}
+}
+
 // WithCustomStrategy sets a custom strategy. This must be used in conjunction with StrategyCustom
 func WithCustomStrategy(s strategies.Strategy) Option {
 	return func(conf *Configuration) {
@@ -36,7 +53,7 @@ func WithCustomStrategy(s strategies.Strategy) Option {
 	}
 }
 
-// WithEventPublishing Enables event publishing (Not Yet Implemented)
+// WithEventPublishing Enables event publishing on the EventChannel
 func WithEventPublishing() Option {
 	return func(conf *Configuration) {
 		conf.publishEvents = true
@@ -49,3 +66,11 @@ func WithoutEventPublishing() Option {
 		conf.publishEvents = false
 	}
 }
+
+// WithHooks Sets hooks run around the evaluation of each provider by the strategies, before the hooks of the provider
+// itself. The hooks of the providers are run regardless of this option. This does not apply to custom strategies.
+func WithHooks(hooks ...of.Hook) Option {
+	return func(conf *Configuration) {
+		conf.hooks = append(conf.hooks, hooks...)
+	}
+}
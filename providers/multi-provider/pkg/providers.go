@@ -22,12 +22,19 @@ type (
 	// MultiProvider Provider used for combining multiple providers
 	MultiProvider struct {
 		providers ProviderMap
+		ordered   []*strategies.NamedProvider
 		metadata  of.Metadata
 		events    chan of.Event
 		status    of.State
 		mu        sync.RWMutex
 		strategy  strategies.Strategy
 		logger    *slog.Logger
+
+		// providerStates holds the state of each provider, the status is their aggregate. Both are guarded by mu.
+		providerStates map[string]of.State
+		initializing   bool
+		stopEvents     context.CancelFunc
+		workers        sync.WaitGroup
 	}
 
 	// Configuration MultiProvider's internal configuration
@@ -39,7 +46,9 @@ type (
 		publishEvents    bool
 		metadata         *of.Metadata //nolint unused
 		timeout          time.Duration
-		hooks            []of.Hook //nolint unused - Not implemented yet
+		hooks            []of.Hook
+		objectComparator strategies.ObjectComparator
+		shadowOptions    []strategies.ShadowOption
 	}
 
 	// EvaluationStrategy Defines a strategy to use for resolving the result from multiple providers
@@ -63,15 +72,18 @@ const (
 	// StrategyCustom allows for using a custom Strategy implementation. If this is set you MUST use the WithCustomStrategy
 	// option to set it
 	StrategyCustom EvaluationStrategy = "strategy-custom"
+	// StrategyShadow The result of the first provider is returned. The other providers are evaluated in the background
+	// and mismatches with the first provider are reported, see WithShadowOptions.
+	StrategyShadow EvaluationStrategy = strategies.StrategyShadow
 )
 
 var _ of.FeatureProvider = (*MultiProvider)(nil)
 
-// AsNamedProviderSlice Converts the map into a slice of NamedProvider instances
+// AsNamedProviderSlice Converts the map into a slice of NamedProvider instances, sorted by name
 func (m ProviderMap) AsNamedProviderSlice() []*strategies.NamedProvider {
 	s := make([]*strategies.NamedProvider, 0, len(m))
-	for name, provider := range m {
-		s = append(s, &strategies.NamedProvider{Name: name, Provider: provider})
+	for _, name := range slices.Sorted(maps.Keys(m)) {
+		s = append(s, &strategies.NamedProvider{Name: name, Provider: m[name]})
 	}
 
 	return s
@@ -82,13 +94,12 @@ func (m ProviderMap) Size() int {
 	return len(m.AsNamedProviderSlice())
 }
 
-func (m ProviderMap) buildMetadata() of.Metadata {
+// buildMetadata names the multi-provider after its providers, in the order they are consulted
+func buildMetadata(providers []*strategies.NamedProvider) of.Metadata {
 	var separator string
 	metaName := "MultiProvider {"
-	names := slices.Collect(maps.Keys(m))
-	slices.Sort(names)
-	for _, name := range names {
-		metaName = fmt.Sprintf("%s%s%s: %s", metaName, separator, name, m[name].Metadata().Name)
+	for _, p := range providers {
+		metaName = fmt.Sprintf("%s%s%s: %s", metaName, separator, p.Name, p.Provider.Metadata().Name)
 		if separator == "" {
 			separator = ", "
 		}
@@ -99,22 +110,55 @@ func (m ProviderMap) buildMetadata() of.Metadata {
 	}
 }
 
-// NewMultiProvider returns the unified interface of multiple providers for interaction.
+// NewMultiProvider returns the unified interface of multiple providers for interaction. The providers are consulted in
+// the order of their names, use NewMultiProviderOrdered to control the order.
 //
 // Deprecated: Use multi.NewProvider() from github.com/open-feature/go-sdk/openfeature/multi instead.
 func NewMultiProvider(providerMap ProviderMap, evaluationStrategy EvaluationStrategy, options ...Option) (*MultiProvider, error) {
 	if len(providerMap) == 0 {
 		return nil, errors.New("providerMap cannot be nil or empty")
 	}
+
+	return newMultiProvider(providerMap.AsNamedProviderSlice(), evaluationStrategy, options...)
+}
+
+// NewMultiProviderOrdered returns the unified interface of multiple providers for interaction. The providers are
+// consulted in the given order, e.g. the FirstMatch strategy returns the result of the first provider knowing the flag.
+//
+// Deprecated: Use multi.NewProvider() from github.com/open-feature/go-sdk/openfeature/multi instead.
+func NewMultiProviderOrdered(providers []*strategies.NamedProvider, evaluationStrategy EvaluationStrategy, options ...Option) (*MultiProvider, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("providers cannot be nil or empty")
+	}
+
+	return newMultiProvider(providers, evaluationStrategy, options...)
+}
+
+func newMultiProvider(providers []*strategies.NamedProvider, evaluationStrategy EvaluationStrategy, options ...Option) (*MultiProvider, error) {
 	// Validate Providers
-	for name, provider := range providerMap {
-		if name == "" {
+	providerMap := make(ProviderMap, len(providers))
+	for _, p := range providers {
+		if p == nil {
+			return nil, errors.New("named provider cannot be nil")
+		}
+
+		if p.Name == "" {
 			return nil, errors.New("provider name cannot be the empty string")
 		}
 
-		if provider == nil {
-			return nil, fmt.Errorf("provider %s cannot be nil", name)
+		if p.Provider == nil {
+			return nil, fmt.Errorf("provider %s cannot be nil", p.Name)
+		}
+
+		if _, ok := providerMap[p.Name]; ok {
+			return nil, fmt.Errorf("provider name %s is not unique", p.Name)
 		}
+		providerMap[p.Name] = p.Provider
+	}
+	// the strategies keep their own copy of the order
+	ordered := make([]*strategies.NamedProvider, 0, len(providers))
+	for _, p := range providers {
+		ordered = append(ordered, &strategies.NamedProvider{Name: p.Name, Provider: p.Provider})
 	}
 
 	config := &Configuration{
@@ -137,9 +181,13 @@ func NewMultiProvider(providerMap ProviderMap, evaluationStrategy EvaluationStra
 
 	multiProvider := &MultiProvider{
 		providers: providerMap,
+		ordered:   ordered,
 		events:    eventChannel,
 		logger:    logger,
-		metadata:  providerMap.buildMetadata(),
+		metadata:  buildMetadata(ordered),
+		status:    of.NotReadyState,
+
+		providerStates: make(map[string]of.State, len(ordered)),
 	}
 
 	var zeroDuration time.Duration
@@ -147,14 +195,32 @@ func NewMultiProvider(providerMap ProviderMap, evaluationStrategy EvaluationStra
 		config.timeout = 5 * time.Second
 	}
 
+	// the strategies evaluate the providers, including the fallback provider, with their hooks
+	evaluated := withHooks(ordered, config.hooks)
+
 	var strategy strategies.Strategy
 	switch evaluationStrategy {
 	case StrategyFirstMatch:
-		strategy = strategies.NewFirstMatchStrategy(multiProvider.Providers())
+		strategy = strategies.NewFirstMatchStrategy(evaluated)
 	case StrategyFirstSuccess:
-		strategy = strategies.NewFirstSuccessStrategy(multiProvider.Providers(), config.timeout)
+		strategy = strategies.NewFirstSuccessStrategy(evaluated, config.timeout)
 	case StrategyComparison:
-		strategy = strategies.NewComparisonStrategy(multiProvider.Providers(), config.fallbackProvider)
+		fallback := config.fallbackProvider
+		if fallback != nil {
+			fallback = &hookedProvider{FeatureProvider: fallback, hooks: config.hooks}
+		}
+		strategy = strategies.NewComparisonStrategy(evaluated, fallback,
+			strategies.WithObjectComparator(config.objectComparator))
+	case StrategyShadow:
+		options := []strategies.ShadowOption{
+			strategies.WithShadowTimeout(config.timeout),
+			strategies.WithShadowComparator(config.objectComparator),
+		}
+		shadow, err := strategies.NewShadowStrategy(evaluated, append(options, config.shadowOptions...)...)
+		if err != nil {
+			return nil, err
+		}
+		strategy = shadow
 	case StrategyCustom:
 		if config.customStrategy != nil {
 			strategy = config.customStrategy
@@ -169,9 +235,13 @@ func NewMultiProvider(providerMap ProviderMap, evaluationStrategy EvaluationStra
 	return multiProvider, nil
 }
 
-// Providers Returns slice of providers wrapped in NamedProvider structs
+// Providers Returns slice of providers wrapped in NamedProvider structs, in the order they are consulted
 func (mp *MultiProvider) Providers() []*strategies.NamedProvider {
-	return mp.providers.AsNamedProviderSlice()
+	providers := make([]*strategies.NamedProvider, 0, len(mp.ordered))
+	for _, p := range mp.ordered {
+		providers = append(providers, &strategies.NamedProvider{Name: p.Name, Provider: p.Provider})
+	}
+	return providers
 }
 
 // ProvidersByName Returns the internal ProviderMap of the MultiProvider
@@ -189,9 +259,9 @@ func (mp *MultiProvider) Metadata() of.Metadata {
 	return mp.metadata
 }
 
-// Hooks returns a collection of.Hook defined by this provider
+// Hooks returns a collection of.Hook defined by this provider. The hooks set with WithHooks and the hooks of the
+// providers are run around the evaluation of each provider instead.
 func (mp *MultiProvider) Hooks() []of.Hook {
-	// Hooks that should be included with the provider
 	return []of.Hook{}
 }
 
@@ -220,17 +290,41 @@ func (mp *MultiProvider) ObjectEvaluation(ctx context.Context, flag string, defa
 	return mp.strategy.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
 }
 
-// Init will run the initialize method for all of provides and aggregate the errors.
+// Init will run the initialize method for all of provides and aggregate the errors. The events of the providers are
+// observed from then on to track their state.
 func (mp *MultiProvider) Init(evalCtx of.EvaluationContext) error {
-	var eg errgroup.Group
+	ctx, cancel := context.WithCancel(context.Background())
+	mp.mu.Lock()
+	if mp.stopEvents != nil {
+		mp.stopEvents()
+	}
+	mp.stopEvents = cancel
+	mp.initializing = true
+	for name := range mp.providers {
+		mp.providerStates[name] = of.NotReadyState
+	}
+	mp.status = of.NotReadyState
+	mp.mu.Unlock()
+
+	// background evaluations stopped by a previous shutdown start again
+	if opener, ok := mp.strategy.(interface{ Open() }); ok {
+		opener.Open()
+	}
+
+	// providers may emit events during their initialization
+	mp.startEventForwarding(ctx)
 
+	var eg errgroup.Group
 	for name, provider := range mp.providers {
 		eg.Go(func() error {
 			stateHandle, ok := provider.(of.StateHandler)
 			if !ok {
+				mp.updateProviderState(name, of.ReadyState)
 				return nil
 			}
-			if err := stateHandle.Init(evalCtx); err != nil {
+			err := stateHandle.Init(evalCtx)
+			mp.updateProviderState(name, initState(err))
+			if err != nil {
 				return &mperr.ProviderError{
 					Err:          err,
 					ProviderName: name,
@@ -241,29 +335,38 @@ func (mp *MultiProvider) Init(evalCtx of.EvaluationContext) error {
 		})
 	}
 
-	if err := eg.Wait(); err != nil {
-		mp.mu.Lock()
-		defer mp.mu.Unlock()
-		mp.status = of.ErrorState
-
-		return err
-	}
+	err := eg.Wait()
 
 	mp.mu.Lock()
 	defer mp.mu.Unlock()
-	mp.status = of.ReadyState
-	return nil
+	mp.initializing = false
+	mp.status = aggregateState(mp.providerStates)
+
+	return err
 }
 
-// Status the current status of the MultiProvider
+// Status the current status of the MultiProvider, the state of highest precedence among its providers:
+// FATAL, NOT_READY, ERROR, STALE and READY
 func (mp *MultiProvider) Status() of.State {
 	mp.mu.RLock()
 	defer mp.mu.RUnlock()
 	return mp.status
 }
 
-// Shutdown Shuts down all internal providers
+// Shutdown Shuts down all internal providers and stops observing their events
 func (mp *MultiProvider) Shutdown() {
+	mp.mu.Lock()
+	if mp.stopEvents != nil {
+		mp.stopEvents()
+		mp.stopEvents = nil
+	}
+	mp.mu.Unlock()
+
+	// background evaluations, e.g. of the shadow strategy, stop and complete before their providers are shut down
+	if closer, ok := mp.strategy.(interface{ Close() }); ok {
+		closer.Close()
+	}
+
 	var wg sync.WaitGroup
 	for _, provider := range mp.providers {
 		wg.Add(1)
@@ -276,9 +379,19 @@ func (mp *MultiProvider) Shutdown() {
 	}
 
 	wg.Wait()
+	mp.workers.Wait()
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	for name := range mp.providers {
+		mp.providerStates[name] = of.NotReadyState
+	}
+	mp.status = of.NotReadyState
 }
 
-// EventChannel the channel events are emitted on (Not Yet Implemented)
+// EventChannel the channel events are emitted on if event publishing is enabled. Configuration changes of the providers
+// are re-emitted with the name of the originating provider, state changes of the providers are emitted if they change
+// the aggregate status.
 func (mp *MultiProvider) EventChannel() <-chan of.Event {
 	return mp.events
 }
@@ -1,6 +1,7 @@
 package multiprovider
 
 import (
+	"context"
 	"errors"
 	"regexp"
 	"testing"
@@ -86,6 +87,114 @@ func TestMultiProvider_NewMultiProvider(t *testing.T) {
 	})
 }
 
+func TestMultiProvider_NewMultiProviderOrdered(t *testing.T) {
+	flag := func(value string) map[string]imp.InMemoryFlag {
+		return map[string]imp.InMemoryFlag{
+			"greeting": {
+				State:          imp.Enabled,
+				DefaultVariant: "default",
+				Variants:       map[string]interface{}{"default": value},
+			},
+		}
+	}
+
+	t.Run("empty providers returns an error", func(t *testing.T) {
+		_, err := NewMultiProviderOrdered(nil, strategies.StrategyFirstMatch)
+		require.EqualError(t, err, "providers cannot be nil or empty")
+	})
+
+	t.Run("duplicate provider names return an error", func(t *testing.T) {
+		_, err := NewMultiProviderOrdered([]*strategies.NamedProvider{
+			{Name: "provider1", Provider: imp.NewInMemoryProvider(map[string]imp.InMemoryFlag{})},
+			{Name: "provider1", Provider: imp.NewInMemoryProvider(map[string]imp.InMemoryFlag{})},
+		}, strategies.StrategyFirstMatch)
+		require.EqualError(t, err, "provider name provider1 is not unique")
+	})
+
+	t.Run("nil provider returns an error", func(t *testing.T) {
+		_, err := NewMultiProviderOrdered([]*strategies.NamedProvider{{Name: "provider1"}}, strategies.StrategyFirstMatch)
+		require.EqualError(t, err, "provider provider1 cannot be nil")
+	})
+
+	t.Run("providers are consulted in the given order", func(t *testing.T) {
+		mp, err := NewMultiProviderOrdered([]*strategies.NamedProvider{
+			{Name: "remote", Provider: imp.NewInMemoryProvider(flag("remote"))},
+			{Name: "local", Provider: imp.NewInMemoryProvider(flag("local"))},
+		}, strategies.StrategyFirstMatch)
+		require.NoError(t, err)
+
+		names := make([]string, 0, 2)
+		for _, p := range mp.Providers() {
+			names = append(names, p.Name)
+		}
+		assert.Equal(t, []string{"remote", "local"}, names)
+		assert.Equal(t, "MultiProvider {remote: InMemoryProvider, local: InMemoryProvider}", mp.Metadata().Name)
+
+		detail := mp.StringEvaluation(context.Background(), "greeting", "", of.FlattenedContext{})
+		assert.Equal(t, "remote", detail.Value)
+		assert.Equal(t, "remote", detail.FlagMetadata[strategies.MetadataSuccessfulProviderName])
+	})
+
+	t.Run("providers of a map are consulted in the order of their names", func(t *testing.T) {
+		providers := make(ProviderMap)
+		providers["b-remote"] = imp.NewInMemoryProvider(flag("remote"))
+		providers["a-local"] = imp.NewInMemoryProvider(flag("local"))
+
+		mp, err := NewMultiProvider(providers, strategies.StrategyFirstMatch)
+		require.NoError(t, err)
+
+		for range 10 {
+			detail := mp.StringEvaluation(context.Background(), "greeting", "", of.FlattenedContext{})
+			assert.Equal(t, "local", detail.Value)
+		}
+	})
+}
+
+func TestMultiProvider_StrategyShadow(t *testing.T) {
+	flag := func(value string) map[string]imp.InMemoryFlag {
+		return map[string]imp.InMemoryFlag{
+			"greeting": {
+				State:          imp.Enabled,
+				DefaultVariant: value,
+				Variants:       map[string]interface{}{value: value},
+			},
+		}
+	}
+
+	mismatches := make(chan strategies.Mismatch, 2)
+	mp, err := NewMultiProviderOrdered([]*strategies.NamedProvider{
+		{Name: "legacy", Provider: imp.NewInMemoryProvider(flag("hello"))},
+		{Name: "candidate", Provider: imp.NewInMemoryProvider(flag("hi"))},
+	}, StrategyShadow, WithShadowOptions(strategies.WithMismatchHandler(func(m strategies.Mismatch) {
+		mismatches <- m
+	})))
+	require.NoError(t, err)
+
+	detail := mp.StringEvaluation(context.Background(), "greeting", "", of.FlattenedContext{})
+	assert.Equal(t, "hello", detail.Value)
+	assert.Equal(t, "legacy", detail.FlagMetadata[strategies.MetadataSuccessfulProviderName])
+
+	// shutting down waits for the shadow evaluation
+	mp.Shutdown()
+	select {
+	case m := <-mismatches:
+		assert.Equal(t, "greeting", m.FlagKey)
+		assert.Equal(t, "legacy", m.Primary.ProviderName)
+		assert.Equal(t, "hello", m.Primary.Value)
+		assert.Equal(t, "candidate", m.Shadow.ProviderName)
+		assert.Equal(t, "hi", m.Shadow.Value)
+	default:
+		t.Fatal("the mismatch was not reported")
+	}
+
+	// shadows are not evaluated once shut down, until the multi-provider is initialized again
+	mp.StringEvaluation(context.Background(), "greeting", "", of.FlattenedContext{})
+	require.NoError(t, mp.Init(of.NewTargetlessEvaluationContext(nil)))
+	mp.StringEvaluation(context.Background(), "greeting", "", of.FlattenedContext{})
+	mp.Shutdown()
+	assert.Len(t, mismatches, 1)
+}
+
 func TestMultiProvider_ProvidersByNamesMethod(t *testing.T) {
 	testProvider1 := imp.NewInMemoryProvider(map[string]imp.InMemoryFlag{})
 	testProvider2 := imp.NewInMemoryProvider(map[string]imp.InMemoryFlag{})
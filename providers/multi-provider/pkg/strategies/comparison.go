@@ -3,10 +3,12 @@ package strategies
 import (
 	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	mperr "github.com/open-feature/go-sdk-contrib/providers/multi-provider/pkg/errors"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"golang.org/x/sync/errgroup"
+	"reflect"
 	"slices"
 	"strings"
 )
@@ -21,18 +23,67 @@ type (
 	ComparisonStrategy struct {
 		providers        []*NamedProvider
 		fallbackProvider of.FeatureProvider
+		objectComparator ObjectComparator
 	}
 
-	comparator[R bool | string | int64 | float64] func(values []R) bool
+	// ObjectComparator Reports whether two object values resolved by providers agree
+	ObjectComparator func(a, b interface{}) bool
+
+	// ComparisonOption Function used for configuring the ComparisonStrategy via the options pattern
+	ComparisonOption func(*ComparisonStrategy)
+
+	comparator[R bool | string | int64 | float64 | interface{}] func(values []R) bool
 )
 
 var _ Strategy = (*ComparisonStrategy)(nil)
 
-func NewComparisonStrategy(providers []*NamedProvider, fallbackProvider of.FeatureProvider) *ComparisonStrategy {
-	return &ComparisonStrategy{
+func NewComparisonStrategy(providers []*NamedProvider, fallbackProvider of.FeatureProvider, options ...ComparisonOption) *ComparisonStrategy {
+	strategy := &ComparisonStrategy{
 		providers:        providers,
 		fallbackProvider: fallbackProvider,
+		objectComparator: JSONEqual,
+	}
+	for _, opt := range options {
+		opt(strategy)
+	}
+	return strategy
+}
+
+// WithObjectComparator Sets the comparator of object values, which defaults to JSONEqual
+func WithObjectComparator(c ObjectComparator) ComparisonOption {
+	return func(s *ComparisonStrategy) {
+		if c != nil {
+			s.objectComparator = c
+		}
+	}
+}
+
+// JSONEqual Reports whether two values are deeply equal once normalized to JSON, e.g. a struct and a map with the
+// same fields, or an int and a float64 with the same value, are equal. Values which can not be encoded as JSON are
+// never equal.
+func JSONEqual(a, b interface{}) bool {
+	normalizedA, err := normalizeJSON(a)
+	if err != nil {
+		return false
+	}
+	normalizedB, err := normalizeJSON(b)
+	if err != nil {
+		return false
 	}
+	return reflect.DeepEqual(normalizedA, normalizedB)
+}
+
+// normalizeJSON Converts a value to its generic JSON representation of maps, slices, strings, float64, bool and nil
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
 }
 
 func (c ComparisonStrategy) Name() EvaluationStrategy {
@@ -217,23 +268,37 @@ func comparisonResolutionError(metadata of.FlagMetadata) of.ResolutionError {
 }
 
 func (c ComparisonStrategy) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx of.FlattenedContext) of.InterfaceResolutionDetail {
-	metadata := make(of.FlagMetadata)
-	metadata[MetadataStrategyUsed] = StrategyComparison
-	metadata[MetadataSuccessfulProviderName] = "none"
-	metadata[MetadataFallbackUsed] = false
+	evalFunc := func(c context.Context, p *NamedProvider) resultWrapper[of.InterfaceResolutionDetail] {
+		result := p.Provider.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+		return resultWrapper[of.InterfaceResolutionDetail]{
+			result: &result,
+			name:   p.Name,
+			value:  result.Value,
+			detail: result.ProviderResolutionDetail,
+		}
+	}
+	compFunc := func(values []interface{}) bool {
+		for _, v := range values[1:] {
+			if !c.objectComparator(values[0], v) {
+				return false
+			}
+		}
 
+		return true
+	}
+	results, metadata := evaluateComparison[of.InterfaceResolutionDetail, interface{}](ctx, c.providers, evalFunc, compFunc, c.fallbackProvider, defaultValue)
 	return of.InterfaceResolutionDetail{
-		Value: defaultValue,
+		Value: results[0].result.Value,
 		ProviderResolutionDetail: of.ProviderResolutionDetail{
-			ResolutionError: of.NewGeneralResolutionError(ErrAggregationNotAllowedText),
-			Reason:          of.DefaultReason,
-			Variant:         "",
+			ResolutionError: comparisonResolutionError(metadata),
+			Reason:          comparisonResolutionReason(metadata),
+			Variant:         results[0].detail.Variant,
 			FlagMetadata:    metadata,
 		},
 	}
 }
 
-func evaluateComparison[R resultConstraint, DV bool | string | int64 | float64](ctx context.Context, providers []*NamedProvider, e evaluator[R], comp comparator[DV], fallbackProvider of.FeatureProvider, defaultVal DV) ([]resultWrapper[R], of.FlagMetadata) {
+func evaluateComparison[R resultConstraint, DV bool | string | int64 | float64 | interface{}](ctx context.Context, providers []*NamedProvider, e evaluator[R], comp comparator[DV], fallbackProvider of.FeatureProvider, defaultVal DV) ([]resultWrapper[R], of.FlagMetadata) {
 	if len(providers) == 1 {
 		result := e(ctx, providers[0])
 		metadata := setFlagMetadata(StrategyComparison, cmp.Or(result.name, providers[0].Name), make(of.FlagMetadata))
@@ -289,7 +354,9 @@ func evaluateComparison[R resultConstraint, DV bool | string | int64 | float64](
 			return []resultWrapper[R]{result}, metadata
 		case r := <-resultChan:
 			results = append(results, *r)
-			resultValues = append(resultValues, r.value.(DV))
+			// object values may be nil
+			value, _ := r.value.(DV)
+			resultValues = append(resultValues, value)
 			if (len(results) + notFoundCount) == len(providers) {
 				goto continueComparison
 			}
@@ -313,6 +380,16 @@ continueComparison:
 	metadata[MetadataStrategyUsed] = StrategyComparison
 	agreement := comp(resultValues)
 	if agreement {
+		// results arrive in completion order, agreeing values may differ in representation, e.g. objects compared as
+		// JSON, so the result of the first provider in configured order is returned
+		order := make(map[string]int, len(providers))
+		for i, p := range providers {
+			order[p.Name] = i
+		}
+		slices.SortStableFunc(results, func(a, b resultWrapper[R]) int {
+			return order[a.name] - order[b.name]
+		})
+
 		metadata[MetadataFallbackUsed] = false
 		metadata[MetadataIsDefault] = false
 		success := make([]string, 0, len(providers))
@@ -329,6 +406,9 @@ continueComparison:
 	if fallbackProvider != nil {
 		fallbackResult := e(ctx, &NamedProvider{Name: "fallback", Provider: fallbackProvider})
 		metadata = fallbackResult.detail.FlagMetadata
+		if metadata == nil {
+			metadata = of.FlagMetadata{}
+		}
 		metadata[MetadataFallbackUsed] = true
 		metadata[MetadataIsDefault] = false
 		metadata[MetadataSuccessfulProviderName] = "fallback"
@@ -1380,36 +1380,142 @@ func Test_ComparisonStrategy_FloatEvaluation(t *testing.T) {
 	})
 }
 
-func Test_ComparisonStrategy_ObjectEvaluation_AlwaysReturnsDefault(t *testing.T) {
-	successVal := struct{ Name string }{Name: "test"}
-	defaultVal := struct{}{}
-	ctrl := gomock.NewController(t)
-	fallback := mocks.NewMockFeatureProvider(ctrl)
-	fallback.EXPECT().FloatEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-	provider1 := mocks.NewMockFeatureProvider(ctrl)
-	configureComparisonProvider(provider1, successVal, true, TestErrorNone)
-	provider2 := mocks.NewMockFeatureProvider(ctrl)
-	configureComparisonProvider(provider2, defaultVal, true, TestErrorError)
-
-	strategy := NewComparisonStrategy([]*NamedProvider{
-		{
-			Name:     "test-provider1",
-			Provider: provider1,
-		},
-		{
-			Name:     "test-provider2",
-			Provider: provider2,
-		},
-	}, fallback)
-
-	result := strategy.ObjectEvaluation(context.Background(), TestFlag, defaultVal, of.FlattenedContext{})
-	assert.Equal(t, defaultVal, result.Value)
-	assert.Equal(t, of.DefaultReason, result.Reason)
-	assert.Equal(t, of.NewGeneralResolutionError(ErrAggregationNotAllowedText), result.ResolutionError)
-	assert.Contains(t, result.FlagMetadata, MetadataStrategyUsed)
-	assert.Equal(t, StrategyComparison, result.FlagMetadata[MetadataStrategyUsed])
-	assert.NotContains(t, result.FlagMetadata, MetadataSuccessfulProviderName+"s")
-	assert.Contains(t, result.FlagMetadata, MetadataSuccessfulProviderName)
-	assert.Equal(t, "none", result.FlagMetadata[MetadataSuccessfulProviderName])
-	assert.False(t, result.FlagMetadata[MetadataFallbackUsed].(bool))
+func Test_ComparisonStrategy_ObjectEvaluation(t *testing.T) {
+	successVal := struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}{Name: "test", Count: 1}
+	// the same object as decoded from JSON by another provider
+	decodedVal := map[string]interface{}{"name": "test", "count": float64(1)}
+	otherVal := map[string]interface{}{"name": "other", "count": float64(1)}
+	defaultVal := map[string]interface{}{}
+
+	t.Run("structurally equal objects agree", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fallback := mocks.NewMockFeatureProvider(ctrl)
+		fallback.EXPECT().ObjectEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		provider1 := mocks.NewMockFeatureProvider(ctrl)
+		configureComparisonProvider[interface{}](provider1, successVal, true, TestErrorNone)
+		provider2 := mocks.NewMockFeatureProvider(ctrl)
+		configureComparisonProvider[interface{}](provider2, decodedVal, true, TestErrorNone)
+
+		strategy := NewComparisonStrategy([]*NamedProvider{
+			{
+				Name:     "test-provider1",
+				Provider: provider1,
+			},
+			{
+				Name:     "test-provider2",
+				Provider: provider2,
+			},
+		}, fallback)
+
+		result := strategy.ObjectEvaluation(context.Background(), TestFlag, defaultVal, of.FlattenedContext{})
+		assert.NoError(t, result.Error())
+		assert.Equal(t, ReasonAggregated, result.Reason)
+		// the value of the first provider in configured order, regardless of which provider completed first
+		assert.Equal(t, successVal, result.Value)
+		assert.Equal(t, "test-provider1, test-provider2", result.FlagMetadata[MetadataSuccessfulProviderName+"s"])
+		assert.False(t, result.FlagMetadata[MetadataFallbackUsed].(bool))
+	})
+
+	t.Run("comparison failure uses fallback", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fallback := mocks.NewMockFeatureProvider(ctrl)
+		fallback.EXPECT().ObjectEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(of.InterfaceResolutionDetail{
+			Value: decodedVal,
+			ProviderResolutionDetail: of.ProviderResolutionDetail{
+				Variant:      "on",
+				FlagMetadata: make(of.FlagMetadata),
+			},
+		})
+		provider1 := mocks.NewMockFeatureProvider(ctrl)
+		configureComparisonProvider[interface{}](provider1, successVal, true, TestErrorNone)
+		provider2 := mocks.NewMockFeatureProvider(ctrl)
+		configureComparisonProvider[interface{}](provider2, otherVal, true, TestErrorNone)
+
+		strategy := NewComparisonStrategy([]*NamedProvider{
+			{
+				Name:     "test-provider1",
+				Provider: provider1,
+			},
+			{
+				Name:     "test-provider2",
+				Provider: provider2,
+			},
+		}, fallback)
+
+		result := strategy.ObjectEvaluation(context.Background(), TestFlag, defaultVal, of.FlattenedContext{})
+		assert.Equal(t, decodedVal, result.Value)
+		assert.Equal(t, ReasonAggregatedFallback, result.Reason)
+		assert.Equal(t, "fallback", result.FlagMetadata[MetadataSuccessfulProviderName])
+		assert.True(t, result.FlagMetadata[MetadataFallbackUsed].(bool))
+	})
+
+	t.Run("custom comparator", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fallback := mocks.NewMockFeatureProvider(ctrl)
+		fallback.EXPECT().ObjectEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		provider1 := mocks.NewMockFeatureProvider(ctrl)
+		configureComparisonProvider[interface{}](provider1, decodedVal, true, TestErrorNone)
+		provider2 := mocks.NewMockFeatureProvider(ctrl)
+		configureComparisonProvider[interface{}](provider2, otherVal, true, TestErrorNone)
+
+		// objects agree on their count
+		sameCount := func(a, b interface{}) bool {
+			return a.(map[string]interface{})["count"] == b.(map[string]interface{})["count"]
+		}
+		strategy := NewComparisonStrategy([]*NamedProvider{
+			{
+				Name:     "test-provider1",
+				Provider: provider1,
+			},
+			{
+				Name:     "test-provider2",
+				Provider: provider2,
+			},
+		}, fallback, WithObjectComparator(sameCount))
+
+		result := strategy.ObjectEvaluation(context.Background(), TestFlag, defaultVal, of.FlattenedContext{})
+		assert.NoError(t, result.Error())
+		assert.Equal(t, ReasonAggregated, result.Reason)
+		assert.False(t, result.FlagMetadata[MetadataFallbackUsed].(bool))
+	})
+
+	t.Run("non FLAG_NOT_FOUND error causes default", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fallback := mocks.NewMockFeatureProvider(ctrl)
+		fallback.EXPECT().ObjectEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		provider1 := mocks.NewMockFeatureProvider(ctrl)
+		configureComparisonProvider[interface{}](provider1, successVal, true, TestErrorNone)
+		provider2 := mocks.NewMockFeatureProvider(ctrl)
+		configureComparisonProvider[interface{}](provider2, defaultVal, true, TestErrorError)
+
+		strategy := NewComparisonStrategy([]*NamedProvider{
+			{
+				Name:     "test-provider1",
+				Provider: provider1,
+			},
+			{
+				Name:     "test-provider2",
+				Provider: provider2,
+			},
+		}, fallback)
+
+		result := strategy.ObjectEvaluation(context.Background(), TestFlag, defaultVal, of.FlattenedContext{})
+		assert.Equal(t, defaultVal, result.Value)
+		assert.Equal(t, of.DefaultReason, result.Reason)
+		assert.Equal(t, of.GeneralCode, result.ResolutionDetail().ErrorCode)
+		assert.Equal(t, "none", result.FlagMetadata[MetadataSuccessfulProviderName])
+		assert.False(t, result.FlagMetadata[MetadataFallbackUsed].(bool))
+	})
+}
+
+func TestJSONEqual(t *testing.T) {
+	assert.True(t, JSONEqual(map[string]interface{}{"a": []int{1, 2}}, map[string]interface{}{"a": []interface{}{1.0, 2.0}}))
+	assert.True(t, JSONEqual(nil, nil))
+	assert.False(t, JSONEqual(map[string]interface{}{"a": []int{1, 2}}, map[string]interface{}{"a": []int{2, 1}}))
+	assert.False(t, JSONEqual(map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1, "b": 2}))
+	// values which can not be encoded are never equal
+	assert.False(t, JSONEqual(func() {}, func() {}))
 }
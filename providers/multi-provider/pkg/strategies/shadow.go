@@ -0,0 +1,317 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	StrategyShadow = "strategy-shadow"
+
+	// ShadowScopeName is the instrumentation scope name of the shadow metrics
+	ShadowScopeName = "github.com/open-feature/go-sdk-contrib/providers/multi-provider"
+
+	shadowMismatches = "feature_flag.multiprovider.shadow_mismatch"
+	shadowDropped    = "feature_flag.multiprovider.shadow_dropped"
+
+	shadowPrimaryProvider = "feature_flag.multiprovider.primary_provider"
+	shadowShadowProvider  = "feature_flag.multiprovider.shadow_provider"
+
+	defaultShadowConcurrency = 10
+	defaultShadowTimeout     = 5 * time.Second
+)
+
+type (
+	// ShadowStrategy returns the result of the primary provider, the first provider, without waiting for the other
+	// providers. The other providers are evaluated in the background as shadows, and their results are compared to the
+	// result of the primary provider to report mismatches, e.g. to verify a migration between providers in production.
+	//
+	// The number of concurrent shadow evaluations is bounded, shadow evaluations exceeding the bound are dropped rather
+	// than delaying evaluations.
+	ShadowStrategy struct {
+		primary     *NamedProvider
+		shadows     []*NamedProvider
+		onMismatch  MismatchHandler
+		comparator  ObjectComparator
+		timeout     time.Duration
+		concurrency int
+		slots       chan struct{}
+		inFlight    sync.WaitGroup
+
+		// mu guards closed, so no shadow evaluation is added to inFlight while Close waits for it
+		mu     sync.Mutex
+		closed bool
+
+		meterProvider metric.MeterProvider
+		mismatches    metric.Int64Counter
+		dropped       metric.Int64Counter
+	}
+
+	// ShadowResult is the result of a provider evaluated by the ShadowStrategy
+	ShadowResult struct {
+		ProviderName string
+		Value        interface{}
+		Variant      string
+		ErrorCode    of.ErrorCode
+		ErrorMessage string
+	}
+
+	// Mismatch is a shadow result differing from the result of the primary provider
+	Mismatch struct {
+		FlagKey  string
+		FlagType of.Type
+		Primary  ShadowResult
+		Shadow   ShadowResult
+	}
+
+	// MismatchHandler is called for each mismatch. It is called from the background shadow evaluations, so it must be
+	// safe for concurrent use.
+	MismatchHandler func(mismatch Mismatch)
+
+	// ShadowOption Function used for configuring the ShadowStrategy via the options pattern
+	ShadowOption func(*ShadowStrategy)
+
+	shadowEvaluator func(ctx context.Context, p *NamedProvider) (interface{}, of.ProviderResolutionDetail)
+)
+
+var _ Strategy = (*ShadowStrategy)(nil)
+
+// WithMismatchHandler Sets the handler called for each mismatch
+func WithMismatchHandler(h MismatchHandler) ShadowOption {
+	return func(s *ShadowStrategy) {
+		s.onMismatch = h
+	}
+}
+
+// WithShadowConcurrency Sets the maximum number of concurrent shadow evaluations, 10 by default
+func WithShadowConcurrency(n int) ShadowOption {
+	return func(s *ShadowStrategy) {
+		s.concurrency = n
+	}
+}
+
+// WithShadowTimeout Sets the timeout of shadow evaluations, 5 seconds by default. Shadow evaluations are not cancelled
+// with the context of the evaluation, as they complete after the evaluation returned.
+func WithShadowTimeout(d time.Duration) ShadowOption {
+	return func(s *ShadowStrategy) {
+		if d > 0 {
+			s.timeout = d
+		}
+	}
+}
+
+// WithShadowComparator Sets the comparator of object values, which defaults to JSONEqual
+func WithShadowComparator(c ObjectComparator) ShadowOption {
+	return func(s *ShadowStrategy) {
+		if c != nil {
+			s.comparator = c
+		}
+	}
+}
+
+// WithShadowMeterProvider Reports the mismatches and the dropped shadow evaluations as OpenTelemetry counters,
+// attributed with the flag key and the provider names. The values are only passed to the mismatch handler, as they
+// would make the cardinality of the counters unbounded.
+func WithShadowMeterProvider(provider metric.MeterProvider) ShadowOption {
+	return func(s *ShadowStrategy) {
+		s.meterProvider = provider
+	}
+}
+
+// NewShadowStrategy Creates a new ShadowStrategy instance. The first provider is the primary provider, the other
+// providers are its shadows.
+func NewShadowStrategy(providers []*NamedProvider, options ...ShadowOption) (*ShadowStrategy, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("the shadow strategy requires a primary provider")
+	}
+
+	s := &ShadowStrategy{
+		primary:     providers[0],
+		shadows:     providers[1:],
+		comparator:  JSONEqual,
+		timeout:     defaultShadowTimeout,
+		concurrency: defaultShadowConcurrency,
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+
+	if s.concurrency <= 0 {
+		return nil, fmt.Errorf("shadow concurrency must be positive, got %d", s.concurrency)
+	}
+	s.slots = make(chan struct{}, s.concurrency)
+
+	if s.meterProvider != nil {
+		meter := s.meterProvider.Meter(ShadowScopeName)
+		var err error
+		s.mismatches, err = meter.Int64Counter(shadowMismatches,
+			metric.WithDescription("multi-provider shadow evaluation mismatch counter"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create shadow mismatch counter: %w", err)
+		}
+		s.dropped, err = meter.Int64Counter(shadowDropped,
+			metric.WithDescription("multi-provider dropped shadow evaluation counter"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dropped shadow evaluation counter: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *ShadowStrategy) Name() EvaluationStrategy {
+	return StrategyShadow
+}
+
+// Close stops starting shadow evaluations and blocks until the shadow evaluations in flight completed, e.g. before
+// shutting down the providers. The primary provider is still evaluated.
+func (s *ShadowStrategy) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.inFlight.Wait()
+}
+
+// Open starts shadow evaluations again after Close, e.g. once the providers are initialized again
+func (s *ShadowStrategy) Open() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = false
+}
+
+func (s *ShadowStrategy) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx of.FlattenedContext) of.BoolResolutionDetail {
+	value, detail := s.evaluate(ctx, flag, of.Boolean, func(c context.Context, p *NamedProvider) (interface{}, of.ProviderResolutionDetail) {
+		r := p.Provider.BooleanEvaluation(c, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.BoolResolutionDetail{Value: value.(bool), ProviderResolutionDetail: detail}
+}
+
+func (s *ShadowStrategy) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx of.FlattenedContext) of.StringResolutionDetail {
+	value, detail := s.evaluate(ctx, flag, of.String, func(c context.Context, p *NamedProvider) (interface{}, of.ProviderResolutionDetail) {
+		r := p.Provider.StringEvaluation(c, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.StringResolutionDetail{Value: value.(string), ProviderResolutionDetail: detail}
+}
+
+func (s *ShadowStrategy) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx of.FlattenedContext) of.FloatResolutionDetail {
+	value, detail := s.evaluate(ctx, flag, of.Float, func(c context.Context, p *NamedProvider) (interface{}, of.ProviderResolutionDetail) {
+		r := p.Provider.FloatEvaluation(c, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.FloatResolutionDetail{Value: value.(float64), ProviderResolutionDetail: detail}
+}
+
+func (s *ShadowStrategy) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx of.FlattenedContext) of.IntResolutionDetail {
+	value, detail := s.evaluate(ctx, flag, of.Int, func(c context.Context, p *NamedProvider) (interface{}, of.ProviderResolutionDetail) {
+		r := p.Provider.IntEvaluation(c, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.IntResolutionDetail{Value: value.(int64), ProviderResolutionDetail: detail}
+}
+
+func (s *ShadowStrategy) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	value, detail := s.evaluate(ctx, flag, of.Object, func(c context.Context, p *NamedProvider) (interface{}, of.ProviderResolutionDetail) {
+		r := p.Provider.ObjectEvaluation(c, flag, defaultValue, evalCtx)
+		return r.Value, r.ProviderResolutionDetail
+	})
+	return of.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// evaluate evaluates the primary provider and starts the shadow evaluations, which are compared to its result
+func (s *ShadowStrategy) evaluate(ctx context.Context, flag string, flagType of.Type, e shadowEvaluator) (interface{}, of.ProviderResolutionDetail) {
+	value, detail := e(ctx, s.primary)
+	primary := newShadowResult(s.primary.Name, value, detail)
+
+	for _, shadow := range s.shadows {
+		s.startShadow(ctx, flag, flagType, primary, shadow, e)
+	}
+
+	detail.FlagMetadata = setFlagMetadata(StrategyShadow, s.primary.Name, detail.FlagMetadata)
+	return value, detail
+}
+
+// startShadow evaluates a shadow provider in the background, unless the strategy is closed or the maximum number of
+// concurrent shadow evaluations is reached
+func (s *ShadowStrategy) startShadow(ctx context.Context, flag string, flagType of.Type, primary ShadowResult, shadow *NamedProvider, e shadowEvaluator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		if s.dropped != nil {
+			s.dropped.Add(ctx, 1, metric.WithAttributes(shadowAttributes(flag, primary.ProviderName, shadow.Name)...))
+		}
+		return
+	}
+
+	s.inFlight.Add(1)
+	go func() {
+		defer s.inFlight.Done()
+		defer func() { <-s.slots }()
+
+		// the shadow evaluation outlives the evaluation, it keeps the values of its context but not its cancellation
+		shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+		defer cancel()
+
+		value, detail := e(shadowCtx, shadow)
+		result := newShadowResult(shadow.Name, value, detail)
+		if s.agree(flagType, primary, result) {
+			return
+		}
+
+		if s.mismatches != nil {
+			s.mismatches.Add(shadowCtx, 1, metric.WithAttributes(shadowAttributes(flag, primary.ProviderName, shadow.Name)...))
+		}
+		if s.onMismatch != nil {
+			s.onMismatch(Mismatch{FlagKey: flag, FlagType: flagType, Primary: primary, Shadow: result})
+		}
+	}()
+}
+
+// agree reports whether the results agree. Results agree if they have the same value, or if they fail with the same
+// error code.
+func (s *ShadowStrategy) agree(flagType of.Type, primary ShadowResult, shadow ShadowResult) bool {
+	if primary.ErrorCode != shadow.ErrorCode {
+		return false
+	}
+	if primary.ErrorCode != "" {
+		return true
+	}
+	if flagType == of.Object {
+		return s.comparator(primary.Value, shadow.Value)
+	}
+	return primary.Value == shadow.Value
+}
+
+func newShadowResult(name string, value interface{}, detail of.ProviderResolutionDetail) ShadowResult {
+	resolution := detail.ResolutionDetail()
+	return ShadowResult{
+		ProviderName: name,
+		Value:        value,
+		Variant:      detail.Variant,
+		ErrorCode:    resolution.ErrorCode,
+		ErrorMessage: resolution.ErrorMessage,
+	}
+}
+
+func shadowAttributes(flag string, primary string, shadow string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("feature_flag.key", flag),
+		attribute.String(shadowPrimaryProvider, primary),
+		attribute.String(shadowShadowProvider, shadow),
+	}
+}
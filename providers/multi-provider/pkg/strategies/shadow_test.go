@@ -0,0 +1,266 @@
+package strategies
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk-contrib/providers/multi-provider/internal/mocks"
+	of "github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/mock/gomock"
+)
+
+// mismatchRecorder records the reported mismatches
+type mismatchRecorder struct {
+	mu         sync.Mutex
+	mismatches []Mismatch
+}
+
+func (r *mismatchRecorder) handle(m Mismatch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mismatches = append(r.mismatches, m)
+}
+
+func (r *mismatchRecorder) recorded() []Mismatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Mismatch{}, r.mismatches...)
+}
+
+// counterValues returns the values of a counter by shadow provider name
+func counterValues(t *testing.T, reader sdkmetric.Reader, name string) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				shadow, _ := dp.Attributes.Value(attribute.Key(shadowShadowProvider))
+				values[shadow.AsString()] += dp.Value
+			}
+		}
+	}
+	return values
+}
+
+func Test_ShadowStrategy_Mismatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockFeatureProvider(ctrl)
+	agreeing := mocks.NewMockFeatureProvider(ctrl)
+	differing := mocks.NewMockFeatureProvider(ctrl)
+	configureComparisonProvider(primary, true, true, TestErrorNone)
+	configureComparisonProvider(agreeing, true, true, TestErrorNone)
+	configureComparisonProvider(differing, false, false, TestErrorNone)
+
+	rec := &mismatchRecorder{}
+	reader := sdkmetric.NewManualReader()
+	strategy, err := NewShadowStrategy([]*NamedProvider{
+		{Name: "primary", Provider: primary},
+		{Name: "agreeing", Provider: agreeing},
+		{Name: "differing", Provider: differing},
+	}, WithMismatchHandler(rec.handle), WithShadowMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	require.NoError(t, err)
+
+	result := strategy.BooleanEvaluation(context.Background(), TestFlag, false, of.FlattenedContext{})
+	require.NoError(t, result.Error())
+	assert.True(t, result.Value)
+	assert.Equal(t, StrategyShadow, result.FlagMetadata[MetadataStrategyUsed])
+	assert.Equal(t, "primary", result.FlagMetadata[MetadataSuccessfulProviderName])
+
+	strategy.Close()
+	assert.Equal(t, []Mismatch{{
+		FlagKey:  TestFlag,
+		FlagType: of.Boolean,
+		Primary:  ShadowResult{ProviderName: "primary", Value: true, Variant: "on"},
+		Shadow:   ShadowResult{ProviderName: "differing", Value: false, Variant: "off"},
+	}}, rec.recorded())
+	assert.Equal(t, map[string]int64{"differing": 1}, counterValues(t, reader, shadowMismatches))
+}
+
+func Test_ShadowStrategy_ErrorMismatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockFeatureProvider(ctrl)
+	missing := mocks.NewMockFeatureProvider(ctrl)
+	configureComparisonProvider(primary, "on", true, TestErrorNone)
+	configureComparisonProvider(missing, "default", true, TestErrorNotFound)
+
+	rec := &mismatchRecorder{}
+	strategy, err := NewShadowStrategy([]*NamedProvider{
+		{Name: "primary", Provider: primary},
+		{Name: "missing", Provider: missing},
+	}, WithMismatchHandler(rec.handle))
+	require.NoError(t, err)
+
+	result := strategy.StringEvaluation(context.Background(), TestFlag, "default", of.FlattenedContext{})
+	assert.Equal(t, "on", result.Value)
+
+	strategy.Close()
+	mismatches := rec.recorded()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "missing", mismatches[0].Shadow.ProviderName)
+	assert.Equal(t, of.FlagNotFoundCode, mismatches[0].Shadow.ErrorCode)
+	assert.Equal(t, "not found", mismatches[0].Shadow.ErrorMessage)
+}
+
+func Test_ShadowStrategy_ObjectValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockFeatureProvider(ctrl)
+	shadow := mocks.NewMockFeatureProvider(ctrl)
+	// the shadow decodes numbers differently, the values are equal as JSON
+	configureComparisonProvider(primary, map[string]interface{}{"limit": 10}, true, TestErrorNone)
+	configureComparisonProvider(shadow, map[string]interface{}{"limit": float64(10)}, true, TestErrorNone)
+
+	rec := &mismatchRecorder{}
+	strategy, err := NewShadowStrategy([]*NamedProvider{
+		{Name: "primary", Provider: primary},
+		{Name: "shadow", Provider: shadow},
+	}, WithMismatchHandler(rec.handle))
+	require.NoError(t, err)
+
+	result := strategy.ObjectEvaluation(context.Background(), TestFlag, nil, of.FlattenedContext{})
+	assert.Equal(t, map[string]interface{}{"limit": 10}, result.Value)
+
+	strategy.Close()
+	assert.Empty(t, rec.recorded())
+}
+
+func Test_ShadowStrategy_DoesNotWaitForShadows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockFeatureProvider(ctrl)
+	slow := mocks.NewMockFeatureProvider(ctrl)
+	primary.EXPECT().IntEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(of.IntResolutionDetail{Value: 1}).Times(2)
+
+	release := make(chan struct{})
+	var canceled bool
+	slow.EXPECT().IntEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _ int64, _ of.FlattenedContext) of.IntResolutionDetail {
+			<-release
+			canceled = ctx.Err() != nil
+			return of.IntResolutionDetail{Value: 2}
+		}).Times(1)
+
+	rec := &mismatchRecorder{}
+	reader := sdkmetric.NewManualReader()
+	strategy, err := NewShadowStrategy([]*NamedProvider{
+		{Name: "primary", Provider: primary},
+		{Name: "slow", Provider: slow},
+	}, WithMismatchHandler(rec.handle), WithShadowConcurrency(1),
+		WithShadowMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	require.NoError(t, err)
+
+	// the evaluation returns while the shadow is still evaluating, even though its context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	result := strategy.IntEvaluation(ctx, TestFlag, 0, of.FlattenedContext{})
+	cancel()
+	assert.Equal(t, int64(1), result.Value)
+
+	// the shadow evaluation exceeding the concurrency is dropped
+	result = strategy.IntEvaluation(context.Background(), TestFlag, 0, of.FlattenedContext{})
+	assert.Equal(t, int64(1), result.Value)
+	assert.Equal(t, map[string]int64{"slow": 1}, counterValues(t, reader, shadowDropped))
+
+	close(release)
+	strategy.Close()
+	assert.False(t, canceled)
+	require.Len(t, rec.recorded(), 1)
+	assert.Equal(t, int64(2), rec.recorded()[0].Shadow.Value)
+}
+
+func Test_ShadowStrategy_Timeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockFeatureProvider(ctrl)
+	slow := mocks.NewMockFeatureProvider(ctrl)
+	configureComparisonProvider(primary, 1.5, true, TestErrorNone)
+	slow.EXPECT().FloatEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, defaultValue float64, _ of.FlattenedContext) of.FloatResolutionDetail {
+			<-ctx.Done()
+			return of.FloatResolutionDetail{
+				Value:                    defaultValue,
+				ProviderResolutionDetail: of.ProviderResolutionDetail{ResolutionError: of.NewGeneralResolutionError(ctx.Err().Error())},
+			}
+		}).Times(1)
+
+	rec := &mismatchRecorder{}
+	strategy, err := NewShadowStrategy([]*NamedProvider{
+		{Name: "primary", Provider: primary},
+		{Name: "slow", Provider: slow},
+	}, WithMismatchHandler(rec.handle), WithShadowTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.5, strategy.FloatEvaluation(context.Background(), TestFlag, 0, of.FlattenedContext{}).Value)
+
+	strategy.Close()
+	require.Len(t, rec.recorded(), 1)
+	assert.Equal(t, of.GeneralCode, rec.recorded()[0].Shadow.ErrorCode)
+}
+
+func Test_ShadowStrategy_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockFeatureProvider(ctrl)
+	shadow := mocks.NewMockFeatureProvider(ctrl)
+	primary.EXPECT().StringEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(of.StringResolutionDetail{Value: "primary"}).AnyTimes()
+	var shadowed atomic.Int64
+	shadow.EXPECT().StringEvaluation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, string, string, of.FlattenedContext) of.StringResolutionDetail {
+			shadowed.Add(1)
+			return of.StringResolutionDetail{Value: "shadow"}
+		}).AnyTimes()
+
+	strategy, err := NewShadowStrategy([]*NamedProvider{
+		{Name: "primary", Provider: primary},
+		{Name: "shadow", Provider: shadow},
+	}, WithShadowConcurrency(100))
+	require.NoError(t, err)
+
+	// evaluations concurrent with closing the strategy either complete their shadow evaluation or do not start it
+	var evaluations sync.WaitGroup
+	for range 50 {
+		evaluations.Add(1)
+		go func() {
+			defer evaluations.Done()
+			strategy.StringEvaluation(context.Background(), TestFlag, "", of.FlattenedContext{})
+		}()
+	}
+	strategy.Close()
+	evaluations.Wait()
+	strategy.Close()
+	started := shadowed.Load()
+
+	// the primary provider is still evaluated once closed, without shadow evaluations
+	result := strategy.StringEvaluation(context.Background(), TestFlag, "", of.FlattenedContext{})
+	assert.Equal(t, "primary", result.Value)
+	strategy.Close()
+	assert.Equal(t, started, shadowed.Load())
+
+	// shadow evaluations start again once reopened
+	strategy.Open()
+	strategy.StringEvaluation(context.Background(), TestFlag, "", of.FlattenedContext{})
+	strategy.Close()
+	assert.Equal(t, started+1, shadowed.Load())
+}
+
+func TestNewShadowStrategy_Invalid(t *testing.T) {
+	_, err := NewShadowStrategy(nil)
+	assert.Error(t, err)
+
+	ctrl := gomock.NewController(t)
+	_, err = NewShadowStrategy([]*NamedProvider{
+		{Name: "primary", Provider: mocks.NewMockFeatureProvider(ctrl)},
+	}, WithShadowConcurrency(0))
+	assert.Error(t, err)
+}
@@ -20,7 +20,8 @@ const (
 	StrategyComparison                       = "strategy-comparison"
 	ReasonAggregated               of.Reason = "AGGREGATED"
 	ReasonAggregatedFallback       of.Reason = "AGGREGATED_FALLBACK"
-	ErrAggregationNotAllowedText             = "object evaluation not allowed for non-comparable types"
+	// Deprecated: object evaluation is supported by the comparison strategy
+	ErrAggregationNotAllowedText = "object evaluation not allowed for non-comparable types"
 )
 
 type (